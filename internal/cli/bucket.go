@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/human"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+// bucketRecord — бакет в ls alias для --output
+type bucketRecord struct {
+	Alias        string     `json:"alias"`
+	Name         string     `json:"name"`
+	CreationDate *time.Time `json:"creation_date,omitempty"`
+}
+
+// bucketResult — итог mb/rb для --output
+type bucketResult struct {
+	Bucket  string `json:"bucket"`
+	Status  string `json:"status"`            // created | removed
+	Deleted int    `json:"deleted,omitempty"` // rb --force: сколько версий удалено
+}
+
+// parseBucketPath — путь вида alias/bucket (без ключа), для mb и rb
+func parseBucketPath(raw, cmd string) (s3Path, error) {
+	sp, err := parseS3Path(strings.TrimSuffix(raw, "/"))
+	if err != nil {
+		return s3Path{}, err
+	}
+	if sp.Bucket == "" || sp.Key != "" {
+		return s3Path{}, fmt.Errorf("для '%s' нужен путь вида alias/bucket, получено: %q", cmd, raw)
+	}
+	return sp, nil
+}
+
+// bucketErrorCode — код ошибки S3 (BucketNotEmpty, BucketAlreadyOwnedByYou, ...), если он есть
+func bucketErrorCode(err error) string {
+	var ae smithy.APIError
+	if errors.As(err, &ae) {
+		return ae.ErrorCode()
+	}
+	return ""
+}
+
+// lsBuckets — ls alias: список бакетов с датами создания
+func lsBuckets(name string, cfg *config.Config, verbose bool) (int, error) {
+	alias, err := cfg.GetAlias(name)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", name)
+		}
+		return 1, err
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 2*time.Minute)
+	defer cancel()
+
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+	buckets, err := client.ListBuckets(ctx)
+	if err != nil {
+		return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+	}
+
+	if structured() {
+		rw := newRecordWriter("alias", "name", "creation_date")
+		for _, b := range buckets {
+			rec := bucketRecord{Alias: name, Name: b.Name}
+			if !b.CreationDate.IsZero() {
+				t := b.CreationDate.UTC()
+				rec.CreationDate = &t
+			}
+			rw.write(rec)
+		}
+		rw.close()
+		return 0, nil
+	}
+	if len(buckets) == 0 {
+		fmt.Println("Бакетов нет. Создать: s3cli mb " + name + "/<bucket>")
+		return 0, nil
+	}
+	fmt.Println("created           bucket")
+	for _, b := range buckets {
+		created := "-"
+		if !b.CreationDate.IsZero() {
+			created = human.Time(b.CreationDate)
+		}
+		fmt.Printf("%-16s  %s/\n", created, b.Name)
+	}
+	return 0, nil
+}
+
+func runMb(args []string, cfgPath string, verbose bool) (int, error) {
+	// mb <alias>/<bucket> [--region R] [--with-lock]
+	region := ""
+	withLock := false
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--region":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --region требует значение\n\n%s", mbUsage())
+			}
+			region = args[i+1]
+			i++
+		case "--with-lock":
+			withLock = true
+		case "-h", "--help":
+			fmt.Print(mbUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'mb': %q\n\n%s", args[i], mbUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket\n\n%s", mbUsage())
+	}
+
+	sp, err := parseBucketPath(pos[0], "mb")
+	if err != nil {
+		return 4, err
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", sp.Alias)
+		}
+		return 1, err
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 2*time.Minute)
+	defer cancel()
+
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+	if err := client.CreateBucket(ctx, sp.Bucket, region, withLock); err != nil {
+		switch bucketErrorCode(err) {
+		case "BucketAlreadyOwnedByYou":
+			return 4, withStatus(fmt.Errorf("бакет %q уже есть и принадлежит вам", sp.Bucket), err)
+		case "BucketAlreadyExists":
+			return 4, withStatus(fmt.Errorf("имя %q уже занято другим бакетом", sp.Bucket), err)
+		case "InvalidBucketName":
+			return 4, withStatus(fmt.Errorf("некорректное имя бакета: %q", sp.Bucket), err)
+		}
+		return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+	}
+
+	if structured() {
+		emitRecord(bucketResult{Bucket: sp.Bucket, Status: "created"}, "bucket", "status", "deleted")
+		return 0, nil
+	}
+	fmt.Printf("Бакет %q создан.\n", sp.Bucket)
+	return 0, nil
+}
+
+func runRb(args []string, cfgPath string, verbose bool) (int, error) {
+	// rb <alias>/<bucket> [--force]
+	force := false
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--force":
+			force = true
+		case "-h", "--help":
+			fmt.Print(rbUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'rb': %q\n\n%s", args[i], rbUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket\n\n%s", rbUsage())
+	}
+
+	sp, err := parseBucketPath(pos[0], "rb")
+	if err != nil {
+		return 4, err
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", sp.Alias)
+		}
+		return 1, err
+	}
+
+	// без общего таймаута: очистка большого бакета идёт дольше пары минут, прервать — Ctrl+C
+	ctx := rootCtx
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+
+	deleted := 0
+	if force {
+		deleted, err = client.DeleteAllVersions(ctx, sp.Bucket, "")
+		if err != nil {
+			if !structured() && deleted > 0 {
+				fmt.Printf("Удалено версий: %d\n", deleted)
+			}
+			return handleAWSError(err, verbose, fmt.Sprintf("Бакет не найден: %s", sp.Bucket), "Доступ запрещён")
+		}
+	}
+	if err := client.DeleteBucket(ctx, sp.Bucket); err != nil {
+		if bucketErrorCode(err) == "BucketNotEmpty" {
+			return 4, withStatus(fmt.Errorf("бакет %q не пуст — удалите содержимое или используйте --force", sp.Bucket), err)
+		}
+		return handleAWSError(err, verbose, fmt.Sprintf("Бакет не найден: %s", sp.Bucket), "Доступ запрещён")
+	}
+
+	if structured() {
+		emitRecord(bucketResult{Bucket: sp.Bucket, Status: "removed", Deleted: deleted}, "bucket", "status", "deleted")
+		return 0, nil
+	}
+	if force {
+		fmt.Printf("Удалено версий: %d\n", deleted)
+	}
+	fmt.Printf("Бакет %q удалён.\n", sp.Bucket)
+	return 0, nil
+}
+
+func mbUsage() string {
+	return `Использование:
+  s3cli mb <alias>/<bucket> [--region R] [--with-lock]
+
+Описание:
+  Создаёт бакет.
+  --region R — регион бакета (LocationConstraint); по умолчанию регион алиаса.
+  --with-lock — включить Object Lock; версионирование включается вместе с ним,
+  потом Object Lock выключить нельзя.
+Пример:
+  s3cli mb s3s7/fao_qa --region eu-west-1
+`
+}
+
+func rbUsage() string {
+	return `Использование:
+  s3cli rb <alias>/<bucket> [--force]
+
+Описание:
+  Удаляет пустой бакет.
+  --force — сначала удалить всё содержимое: все версии объектов и маркеры удаления.
+  Версии под блокировкой Object Lock удалить не получится — команда сообщит об ошибке.
+Пример:
+  s3cli rb s3s7/fao_qa_old --force
+`
+}
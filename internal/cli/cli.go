@@ -5,35 +5,65 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/aws/smithy-go"
 	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/filter"
 	"github.com/wolfsTail/s3cli/internal/human"
 	"github.com/wolfsTail/s3cli/internal/s3client"
 	"github.com/wolfsTail/s3cli/internal/transfer"
 )
 
+// rootCtx — отменяется по Ctrl-C/SIGTERM, чтобы передачи успели прибрать за собой
+var rootCtx = context.Background()
+
 // точка входа
 func Run(argv []string) (int, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// после первого сигнала возвращаем обработку по умолчанию: если что-то не смотрит
+	// на ctx (put - ждёт stdin), второй Ctrl-C должен завершить процесс сразу
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	rootCtx = ctx
+
+	code, err := run(argv)
+	if err != nil && writeError(code, err) {
+		return code, nil
+	}
+	closeArray()
+	return code, err
+}
+
+func run(argv []string) (int, error) {
 	// Глобальные флаги
 	var cfgPath string
 	var verbose bool
 	var noProgress bool
+	var limitRate int64
 
 	if len(argv) == 0 {
 		printUsage()
 		return 0, nil
 	}
-	rest, err := parseGlobalFlags(argv, &cfgPath, &verbose, &noProgress)
+	rest, err := parseGlobalFlags(argv, &cfgPath, &verbose, &noProgress, &limitRate, &output)
 	if err != nil {
 		return 4, err
 	}
+	if structured() {
+		// прогресс-бар мешал бы разбору вывода
+		noProgress = true
+	}
 	if len(rest) == 0 {
 		printUsage()
 		return 0, nil
@@ -45,19 +75,45 @@ func Run(argv []string) (int, error) {
 	case "alias":
 		return runAlias(rest[1:], cfgPath)
 	case "get":
-		return runGet(rest[1:], cfgPath, verbose, !noProgress)
+		return runGet(rest[1:], cfgPath, verbose, !noProgress, limitRate)
 	case "ls":
 		return runLs(rest[1:], cfgPath, verbose)
+	case "tree":
+		return runTree(rest[1:], cfgPath, verbose)
+	case "du":
+		return runDu(rest[1:], cfgPath, verbose)
+	case "find":
+		return runFind(rest[1:], cfgPath, verbose)
+	case "mb":
+		return runMb(rest[1:], cfgPath, verbose)
+	case "rb":
+		return runRb(rest[1:], cfgPath, verbose)
 	case "rm":
 		return runRm(rest[1:], cfgPath, verbose)
+	case "version":
+		return runVersion(rest[1:], cfgPath, verbose)
+	case "undo-rm":
+		return runUndoRm(rest[1:], cfgPath, verbose)
+	case "restore-to":
+		return runRestoreTo(rest[1:], cfgPath, verbose)
+	case "multipart":
+		return runMultipart(rest[1:], cfgPath, verbose)
+	case "lifecycle":
+		return runLifecycle(rest[1:], cfgPath, verbose)
 	case "put":
-		return runPut(rest[1:], cfgPath, verbose, !noProgress)
+		return runPut(rest[1:], cfgPath, verbose, !noProgress, limitRate)
 	case "presign":
 		return runPresign(rest[1:], cfgPath, verbose)
 	case "stat":
 		return runStat(rest[1:], cfgPath, verbose)
 	case "cat":
 		return runCat(rest[1:], cfgPath, verbose)
+	case "sync":
+		return runSync(rest[1:], cfgPath, verbose, !noProgress, limitRate)
+	case "cp":
+		return runCp(rest[1:], cfgPath, verbose, !noProgress, limitRate, false)
+	case "mv":
+		return runCp(rest[1:], cfgPath, verbose, !noProgress, limitRate, true)
 	default:
 		return 4, fmt.Errorf("неизвестная команда: %q\n\n%s", rest[0], usageShort())
 	}
@@ -85,7 +141,7 @@ func runAlias(args []string, cfgPath string) (int, error) {
 
 func aliasAdd(args []string, cfgPath string) (int, error) {
 	// вариант
-	// alias add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style]
+	// alias add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style] [--retries N] [--limit-rate RATE]
 	if len(args) < 4 {
 		return 4, fmt.Errorf("недостаточно аргументов для 'alias add'\n\n%s", aliasAddUsage())
 	}
@@ -97,6 +153,8 @@ func aliasAdd(args []string, cfgPath string) (int, error) {
 	region := ""
 	secure := false
 	pathStyle := false
+	retries := 0
+	limitRate := ""
 
 	// остальные...
 	var i = 4
@@ -114,6 +172,25 @@ func aliasAdd(args []string, cfgPath string) (int, error) {
 		case "--path-style":
 			pathStyle = true
 			i++
+		case "--retries":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --retries требует число\n\n%s", aliasAddUsage())
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				return 4, fmt.Errorf("некорректное значение для --retries: %q", args[i+1])
+			}
+			retries = n
+			i += 2
+		case "--limit-rate":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --limit-rate требует значение, пример: 20MiB/s\n\n%s", aliasAddUsage())
+			}
+			if _, err := parseRate(args[i+1]); err != nil {
+				return 4, err
+			}
+			limitRate = args[i+1]
+			i += 2
 		case "-h", "--help":
 			fmt.Print(aliasAddUsage())
 			return 0, nil
@@ -134,6 +211,8 @@ func aliasAdd(args []string, cfgPath string) (int, error) {
 		SecretKey: sk,
 		Secure:    secure,
 		PathStyle: pathStyle,
+		Retries:   retries,
+		LimitRate: limitRate,
 	})
 	if err != nil {
 		if errors.Is(err, config.ErrInvalidAlias) {
@@ -149,6 +228,10 @@ func aliasAdd(args []string, cfgPath string) (int, error) {
 		return 1, err
 	}
 
+	if structured() {
+		emitRecord(aliasResult{Alias: name, Status: "added"}, "alias", "status")
+		return 0, nil
+	}
 	fmt.Printf("Алиас %q добавлен.\n", name)
 	return 0, nil
 }
@@ -169,6 +252,9 @@ func aliasLs(args []string, cfgPath string) (int, error) {
 	if err != nil {
 		return 1, err
 	}
+	if structured() {
+		return aliasLsRecords(cfg)
+	}
 	if len(cfg.Aliases) == 0 {
 		fmt.Println("Нет алиасов. Добавьте командой: s3cli alias add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style]")
 		return 0, nil
@@ -197,6 +283,37 @@ func aliasLs(args []string, cfgPath string) (int, error) {
 	return 0, nil
 }
 
+// aliasRecord — алиас в alias ls для --output; ключи доступа не выводятся
+type aliasRecord struct {
+	Name      string `json:"name"`
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region"`
+	SSL       bool   `json:"ssl"`
+	PathStyle bool   `json:"path_style"`
+}
+
+// aliasResult — итог alias add/rm для --output
+type aliasResult struct {
+	Alias  string `json:"alias"`
+	Status string `json:"status"`
+}
+
+func aliasLsRecords(cfg *config.Config) (int, error) {
+	names := make([]string, 0, len(cfg.Aliases))
+	for n := range cfg.Aliases {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	rw := newRecordWriter("name", "endpoint", "region", "ssl", "path_style")
+	for _, n := range names {
+		a := cfg.Aliases[n]
+		rw.write(aliasRecord{Name: n, Endpoint: a.Endpoint, Region: a.Region, SSL: a.Secure, PathStyle: a.PathStyle})
+	}
+	rw.close()
+	return 0, nil
+}
+
 func aliasRm(args []string, cfgPath string) (int, error) {
 	// Формат: alias rm <name>
 	if len(args) == 0 {
@@ -226,26 +343,81 @@ func aliasRm(args []string, cfgPath string) (int, error) {
 	if err := config.Save(cfgPath, cfg); err != nil {
 		return 1, err
 	}
+	if structured() {
+		emitRecord(aliasResult{Alias: name, Status: "removed"}, "alias", "status")
+		return 0, nil
+	}
 	fmt.Printf("Алиас %q удалён.\n", name)
 	return 0, nil
 }
 
 func runLs(args []string, cfgPath string, verbose bool) (int, error) {
-	// Формат: ls <alias>/<bucket>/<prefix?>
-	if len(args) == 0 {
-		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/prefix]\n\nПример:\n  s3cli ls s3s7/my-bucket/reports/2025/")
-	}
-	if len(args) > 1 {
-		switch args[1] {
+	// Формат: ls <alias>/<bucket>/<prefix?> [-r [--max-depth N] [--include GLOB] [--exclude GLOB]] [--versions]
+	recursive := false
+	versions := false
+	maxDepth := 0
+	flt := filter.New()
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-r", "--recursive":
+			recursive = true
+		case "--versions":
+			versions = true
+		case "--max-depth":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --max-depth требует число")
+			}
+			n, err := parseMaxDepth(args[i+1])
+			if err != nil {
+				return 4, err
+			}
+			maxDepth = n
+			i++
+		case "--include", "--exclude":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг %s требует шаблон", args[i])
+			}
+			if err := addFilter(flt, args[i], args[i+1]); err != nil {
+				return 4, err
+			}
+			i++
 		case "-h", "--help":
 			fmt.Print(lsUsage())
 			return 0, nil
 		default:
-			return 4, fmt.Errorf("лишний аргумент для 'ls': %q\n\n%s", args[1], lsUsage())
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'ls': %q\n\n%s", args[i], lsUsage())
+			}
+			pos = append(pos, args[i])
 		}
 	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/prefix] или alias\n\nПример:\n  s3cli ls s3s7/my-bucket/reports/2025/")
+	}
+	if !flt.Empty() && !recursive {
+		return 4, fmt.Errorf("--include/--exclude работают только вместе с -r")
+	}
+	if maxDepth > 0 && !recursive {
+		return 4, fmt.Errorf("--max-depth работает только вместе с -r")
+	}
+	if maxDepth > 0 && versions {
+		return 4, fmt.Errorf("--max-depth и --versions вместе не работают")
+	}
 
-	sp, err := parseS3Path(args[0])
+	// ls alias — список бакетов
+	if name := strings.TrimSuffix(strings.TrimPrefix(pos[0], "s3://"), "/"); name != "" && !strings.Contains(name, "/") {
+		if recursive || versions {
+			return 4, fmt.Errorf("-r и --versions работают только с путём alias/bucket[/prefix]")
+		}
+		cfg, err := config.Load(cfgPath)
+		if err != nil {
+			return 1, err
+		}
+		return lsBuckets(name, cfg, verbose)
+	}
+
+	sp, err := parseS3Path(pos[0])
 	if err != nil {
 		return 4, err
 	}
@@ -262,7 +434,28 @@ func runLs(args []string, cfgPath string, verbose bool) (int, error) {
 		return 1, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	prefix := sp.Key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+	}
+
+	if versions {
+		// без общего таймаута, как и у ls -r
+		client, err := s3client.New(rootCtx, alias)
+		if err != nil {
+			return 1, err
+		}
+		return lsVersions(rootCtx, client, sp.Bucket, prefix, recursive, flt, verbose)
+	}
+	if recursive {
+		// без общего таймаута: листинг большого бакета идёт дольше пары минут, прервать — Ctrl+C
+		client, err := s3client.New(rootCtx, alias)
+		if err != nil {
+			return 1, err
+		}
+		return lsRecursive(rootCtx, client, sp.Bucket, prefix, flt, maxDepth, verbose)
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 2*time.Minute)
 	defer cancel()
 
 	client, err := s3client.New(ctx, alias)
@@ -270,15 +463,23 @@ func runLs(args []string, cfgPath string, verbose bool) (int, error) {
 		return 1, err
 	}
 
-	prefix := sp.Key
-	if prefix != "" && !strings.HasSuffix(prefix, "/") {
-	}
-
 	folders, objects, err := client.ListOneLevel(ctx, sp.Bucket, prefix)
 	if err != nil {
 		return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
 	}
 
+	if structured() {
+		rw := newRecordWriter(objectCols...)
+		for _, f := range folders {
+			rw.write(objectRecord{Type: "prefix", Bucket: sp.Bucket, Key: f, Name: strings.TrimPrefix(f, prefix)})
+		}
+		for _, o := range objects {
+			rw.write(newObjectRecord(sp.Bucket, prefix, o))
+		}
+		rw.close()
+		return 0, nil
+	}
+
 	type row struct {
 		isDir bool
 		name  string
@@ -337,11 +538,44 @@ func runLs(args []string, cfgPath string, verbose bool) (int, error) {
 	return 0, nil
 }
 
+// statRecord — результат stat для --output
+type statRecord struct {
+	Bucket       string            `json:"bucket"`
+	Key          string            `json:"key"`
+	Size         int64             `json:"size"`
+	LastModified *time.Time        `json:"last_modified,omitempty"`
+	ETag         string            `json:"etag"`
+	ContentType  string            `json:"content_type"`
+	VersionID    string            `json:"version_id,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
 func runStat(args []string, cfgPath string, verbose bool) (int, error) {
-	if len(args) == 0 {
+	// stat <alias>/<bucket>/<key> [--version-id ID]
+	versionID := ""
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--version-id":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --version-id требует значение")
+			}
+			versionID = args[i+1]
+			i++
+		case "-h", "--help":
+			fmt.Print(statUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'stat': %q\n\n%s", args[i], statUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
 		return 4, fmt.Errorf("нужно указать путь alias/bucket/key\n\n%s", statUsage())
 	}
-	sp, err := parseS3Path(args[0])
+	sp, err := parseS3Path(pos[0])
 	if err != nil {
 		return 4, err
 	}
@@ -361,7 +595,7 @@ func runStat(args []string, cfgPath string, verbose bool) (int, error) {
 		return 1, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := context.WithTimeout(rootCtx, 2*time.Minute)
 	defer cancel()
 
 	client, err := s3client.New(ctx, alias)
@@ -369,27 +603,69 @@ func runStat(args []string, cfgPath string, verbose bool) (int, error) {
 		return 1, err
 	}
 
-	info, err := client.StatObject(ctx, sp.Bucket, sp.Key)
+	notFound := fmt.Sprintf("Объект не найден: %s/%s", sp.Bucket, sp.Key)
+	if versionID != "" {
+		notFound = fmt.Sprintf("Версия не найдена: %s/%s (%s)", sp.Bucket, sp.Key, versionID)
+	}
+	info, err := client.StatVersion(ctx, sp.Bucket, sp.Key, versionID)
 	if err != nil {
-		return handleAWSError(err, verbose,
-			fmt.Sprintf("Объект не найден: %s/%s", sp.Bucket, sp.Key),
-			"Доступ запрещён",
-		)
+		return handleAWSError(err, verbose, notFound, "Доступ запрещён")
+	}
+
+	if structured() {
+		rec := statRecord{
+			Bucket:      sp.Bucket,
+			Key:         info.Key,
+			Size:        info.Size,
+			ETag:        info.ETag,
+			ContentType: info.ContentType,
+			VersionID:   info.VersionID,
+			Metadata:    info.Metadata,
+		}
+		if !info.ModTime.IsZero() {
+			t := info.ModTime.UTC()
+			rec.LastModified = &t
+		}
+		emitRecord(rec, "bucket", "key", "size", "last_modified", "etag", "content_type", "version_id", "metadata")
+		return 0, nil
 	}
-
 	fmt.Printf("Key:          %s\n", info.Key)
 	fmt.Printf("Size:        %d байт\n", info.Size)
 	fmt.Printf("LastModified: %s\n", info.LastModified)
 	fmt.Printf("ETag:          %s\n", info.ETag)
 	fmt.Printf("Content-Type:  %s\n", info.ContentType)
+	if info.VersionID != "" {
+		fmt.Printf("VersionId:     %s\n", info.VersionID)
+	}
 	return 0, nil
 }
 
 func runCat(args []string, cfgPath string, verbose bool) (int, error) {
-	if len(args) == 0 {
+	// cat <alias>/<bucket>/<key> [--version-id ID]
+	versionID := ""
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--version-id":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --version-id требует значение")
+			}
+			versionID = args[i+1]
+			i++
+		case "-h", "--help":
+			fmt.Print(catUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'cat': %q\n\n%s", args[i], catUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
 		return 4, fmt.Errorf("нужно указать путь alias/bucket/key\n\n%s", catUsage())
 	}
-	sp, err := parseS3Path(args[0])
+	sp, err := parseS3Path(pos[0])
 	if err != nil {
 		return 4, err
 	}
@@ -409,7 +685,7 @@ func runCat(args []string, cfgPath string, verbose bool) (int, error) {
 		return 1, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := context.WithTimeout(rootCtx, 2*time.Minute)
 	defer cancel()
 
 	client, err := s3client.New(ctx, alias)
@@ -417,11 +693,12 @@ func runCat(args []string, cfgPath string, verbose bool) (int, error) {
 		return 1, err
 	}
 
-	if err := client.CatObject(ctx, sp.Bucket, sp.Key, os.Stdout); err != nil {
-		return handleAWSError(err, verbose,
-			fmt.Sprintf("Объект не найден: %s/%s", sp.Bucket, sp.Key),
-			"Доступ запрещён",
-		)
+	notFound := fmt.Sprintf("Объект не найден: %s/%s", sp.Bucket, sp.Key)
+	if versionID != "" {
+		notFound = fmt.Sprintf("Версия не найдена: %s/%s (%s)", sp.Bucket, sp.Key, versionID)
+	}
+	if err := client.CatVersion(ctx, sp.Bucket, sp.Key, versionID, os.Stdout); err != nil {
+		return handleAWSError(err, verbose, notFound, "Доступ запрещён")
 	}
 	return 0, nil
 }
@@ -429,21 +706,54 @@ func runCat(args []string, cfgPath string, verbose bool) (int, error) {
 func runRm(args []string, cfgPath string, verbose bool) (int, error) {
 	//   rm <alias>/<bucket>/<key> — удалить объект
 	//   rm -r <alias>/<bucket>/<prefix/> — удалить рекурсивно по префиксу
+	//   rm <alias>/<bucket>/<key> --version-id ID — удалить одну версию навсегда
 	if len(args) == 0 {
 		return 4, fmt.Errorf("нужно указать путь alias/bucket/key или -r alias/bucket/prefix/\n\n%s", rmUsage())
 	}
 
 	recursive := false
-	pos := 0
-	if args[0] == "-r" || args[0] == "--recursive" {
-		recursive = true
-		pos = 1
+	versionID := ""
+	flt := filter.New()
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-r", "--recursive":
+			recursive = true
+		case "--version-id":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --version-id требует значение")
+			}
+			versionID = args[i+1]
+			i++
+		case "--include", "--exclude":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг %s требует шаблон", args[i])
+			}
+			if err := addFilter(flt, args[i], args[i+1]); err != nil {
+				return 4, err
+			}
+			i++
+		case "-h", "--help":
+			fmt.Print(rmUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для rm: %q\n\n%s", args[i], rmUsage())
+			}
+			pos = append(pos, args[i])
+		}
 	}
 
-	if len(args) <= pos {
+	if len(pos) == 0 {
 		return 4, fmt.Errorf("не указан путь для удаления\n\n%s", rmUsage())
 	}
-	target := args[pos]
+	if !flt.Empty() && !recursive {
+		return 4, fmt.Errorf("--include/--exclude работают только вместе с -r")
+	}
+	if versionID != "" && recursive {
+		return 4, fmt.Errorf("--version-id удаляет одну версию одного объекта, с -r не работает")
+	}
+	target := pos[0]
 
 	sp, err := parseS3Path(target)
 	if err != nil {
@@ -465,7 +775,7 @@ func runRm(args []string, cfgPath string, verbose bool) (int, error) {
 		return 1, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	ctx, cancel := context.WithTimeout(rootCtx, 10*time.Minute)
 	defer cancel()
 
 	client, err := s3client.New(ctx, alias)
@@ -482,11 +792,46 @@ func runRm(args []string, cfgPath string, verbose bool) (int, error) {
 		if !strings.HasSuffix(prefix, "/") {
 			prefix += "/"
 		}
-		n, err := client.DeletePrefix(ctx, sp.Bucket, prefix)
+		if flt.Empty() {
+			n, err := client.DeletePrefix(ctx, sp.Bucket, prefix)
+			if err != nil {
+				return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+			}
+			printDeleted(rmRecord{Bucket: sp.Bucket, Key: prefix, Deleted: n})
+			return 0, nil
+		}
+		// с фильтром удаляем только подходящие ключи
+		keys, err := client.ListAllKeys(ctx, sp.Bucket, prefix)
 		if err != nil {
 			return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
 		}
-		fmt.Printf("Удалено объектов: %d\n", n)
+		matched := keys[:0]
+		for _, k := range keys {
+			if flt.Match(strings.TrimPrefix(k, prefix)) {
+				matched = append(matched, k)
+			}
+		}
+		n, err := client.DeleteKeys(ctx, sp.Bucket, matched)
+		if err == nil || !structured() {
+			printDeleted(rmRecord{Bucket: sp.Bucket, Key: prefix, Deleted: n})
+		}
+		if err != nil {
+			return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+		}
+		return 0, nil
+	}
+	if versionID != "" {
+		if err := client.DeleteVersion(ctx, sp.Bucket, sp.Key, versionID); err != nil {
+			return handleAWSError(err, verbose,
+				fmt.Sprintf("Версия не найдена: %s/%s (%s)", sp.Bucket, sp.Key, versionID),
+				"Доступ запрещён",
+			)
+		}
+		if structured() {
+			printDeleted(rmRecord{Bucket: sp.Bucket, Key: sp.Key, VersionID: versionID, Deleted: 1})
+			return 0, nil
+		}
+		fmt.Println("Версия удалена навсегда.")
 		return 0, nil
 	}
 	if strings.HasSuffix(sp.Key, "/") {
@@ -498,22 +843,47 @@ func runRm(args []string, cfgPath string, verbose bool) (int, error) {
 			"Доступ запрещён",
 		)
 	}
+	if structured() {
+		printDeleted(rmRecord{Bucket: sp.Bucket, Key: sp.Key, Deleted: 1})
+		return 0, nil
+	}
 	fmt.Println("Удалено.")
 	return 0, nil
 }
 
-func runPut(args []string, cfgPath string, verbose bool, showProgress bool) (int, error) {
-	// put <local_path> <alias>/<bucket>/<key|prefix/> [-j N]
-	if len(args) < 2 {
-		return 4, fmt.Errorf("нужно указать локальный путь и целевой путь вида alias/bucket/key|prefix/\n\n%s", putUsage())
+// rmRecord — итог rm для --output; key — ключ или префикс
+type rmRecord struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"version_id,omitempty"`
+	Deleted   int    `json:"deleted"`
+}
+
+func printDeleted(rec rmRecord) {
+	if structured() {
+		emitRecord(rec, "bucket", "key", "version_id", "deleted")
+		return
 	}
+	fmt.Printf("Удалено объектов: %d\n", rec.Deleted)
+}
 
-	localPath := args[0]
-	dest := args[1]
+func runPut(args []string, cfgPath string, verbose bool, showProgress bool, limitRate int64) (int, error) {
+	// put <local_path> <alias>/<bucket>/<key|prefix/> [-j N] [--no-resume] [--report FILE]
+	// put --retry-from FILE [-j N] [--report FILE]
 	jobs := 4
+	retries := -1
+	resume := true
+	var partSize int64
+	reportPath := ""
+	retryFrom := ""
+	overwrite := transfer.OverwriteAlways
+	flt := filter.New()
+	preserve, preserveOwner := false, false
+	links := transfer.LinksDefault
+	var pos []string
 
 	// парсинг
-	for i := 2; i < len(args); i++ {
+	for i := 0; i < len(args); i++ {
 		switch args[i] {
 		case "-j", "--jobs":
 			if i+1 >= len(args) {
@@ -525,17 +895,114 @@ func runPut(args []string, cfgPath string, verbose bool, showProgress bool) (int
 			}
 			jobs = n
 			i++
+		case "--no-resume":
+			resume = false
+		case "--part-size":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --part-size требует размер, например 64MiB")
+			}
+			n, err := human.ParseBytes(args[i+1])
+			if err != nil || n < 5<<20 || n > 5<<30 {
+				return 4, fmt.Errorf("некорректное значение для --part-size: %q (от 5MiB до 5GiB)", args[i+1])
+			}
+			partSize = n
+			i++
+		case "--retries":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --retries требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				return 4, fmt.Errorf("некорректное значение для --retries: %q", args[i+1])
+			}
+			retries = n
+			i++
+		case "--report":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --report требует путь к файлу")
+			}
+			reportPath = args[i+1]
+			i++
+		case "--retry-from":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --retry-from требует путь к файлу отчёта")
+			}
+			retryFrom = args[i+1]
+			i++
+		case "--no-clobber", "--update", "--skip-existing-same-size":
+			if err := setOverwrite(&overwrite, args[i]); err != nil {
+				return 4, err
+			}
+		case "--include", "--exclude":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг %s требует шаблон", args[i])
+			}
+			if err := addFilter(flt, args[i], args[i+1]); err != nil {
+				return 4, err
+			}
+			i++
+		case "--preserve":
+			preserve = true
+		case "--preserve-owner":
+			preserve, preserveOwner = true, true
+		case "--follow-symlinks":
+			if err := setLinks(&links, "follow", "follow", "store"); err != nil {
+				return 4, err
+			}
+		case "--links":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --links требует значение: follow или store")
+			}
+			if err := setLinks(&links, args[i+1], "follow", "store"); err != nil {
+				return 4, err
+			}
+			i++
 		case "-h", "--help":
 			fmt.Print(putUsage())
 			return 0, nil
 		default:
-			return 4, fmt.Errorf("неизвестный аргумент для put: %q\n\n%s", args[i], putUsage())
+			if v, ok := strings.CutPrefix(args[i], "--links="); ok {
+				if err := setLinks(&links, v, "follow", "store"); err != nil {
+					return 4, err
+				}
+				continue
+			}
+			if strings.HasPrefix(args[i], "-") && args[i] != "-" {
+				return 4, fmt.Errorf("неизвестный аргумент для put: %q\n\n%s", args[i], putUsage())
+			}
+			pos = append(pos, args[i])
 		}
 	}
 
-	sp, err := parseS3Path(dest)
-	if err != nil {
-		return 4, err
+	var (
+		sp        s3Path
+		rep       *transferReport
+		localPath string
+	)
+	if retryFrom != "" {
+		if len(pos) != 0 {
+			return 4, fmt.Errorf("с --retry-from пути берутся из отчёта, лишний аргумент: %q", pos[0])
+		}
+		r, err := readReport(retryFrom, "put")
+		if err != nil {
+			return 4, err
+		}
+		if len(r.Failed) == 0 {
+			printDone("В отчёте нет незагруженных файлов.", transferRecord{Command: "put", Status: "ok", Bucket: r.Bucket})
+			return 0, nil
+		}
+		rep = &r
+		sp = s3Path{Alias: r.Alias, Bucket: r.Bucket}
+	} else {
+		if len(pos) != 2 {
+			return 4, fmt.Errorf("нужно указать локальный путь и целевой путь вида alias/bucket/key|prefix/\n\n%s", putUsage())
+		}
+		localPath = pos[0]
+		var err error
+		sp, err = parseS3Path(pos[1])
+		if err != nil {
+			return 4, err
+		}
 	}
 
 	cfg, err := config.Load(cfgPath)
@@ -550,7 +1017,7 @@ func runPut(args []string, cfgPath string, verbose bool, showProgress bool) (int
 		return 1, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 24*time.Hour)
+	ctx, cancel := context.WithTimeout(rootCtx, 24*time.Hour)
 	defer cancel()
 
 	client, err := s3client.New(ctx, alias)
@@ -558,25 +1025,83 @@ func runPut(args []string, cfgPath string, verbose bool, showProgress bool) (int
 		return 1, err
 	}
 
+	journalDir, err := config.UploadsDir()
+	if err != nil {
+		return 1, err
+	}
+	limiter, err := rateLimiter(limitRate, alias)
+	if err != nil {
+		return 1, err
+	}
+	opts := transfer.PutOptions{
+		Jobs:          jobs,
+		ShowProgress:  showProgress,
+		Resume:        resume,
+		JournalDir:    journalDir,
+		Retry:         retryPolicy(retries, alias),
+		Limiter:       limiter,
+		Overwrite:     overwrite,
+		Filter:        flt,
+		PartSize:      partSize,
+		Preserve:      preserve,
+		PreserveOwner: preserveOwner,
+		Links:         links,
+	}
+
+	if rep != nil {
+		items := make([]transfer.UploadItem, 0, len(rep.Failed))
+		for _, it := range rep.Failed {
+			items = append(items, transfer.UploadItem{Local: it.Local, Key: it.Key})
+		}
+		stats, err := transfer.UploadFiles(ctx, client.S3, sp.Bucket, items, opts)
+		if err != nil {
+			return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+		}
+		return putSummary(stats, sp, reportPath)
+	}
+
+	if localPath == "-" {
+		if sp.Key == "" || strings.HasSuffix(sp.Key, "/") {
+			return 4, fmt.Errorf("для загрузки из stdin нужно указать полный ключ: alias/bucket/key")
+		}
+		if overwrite != transfer.OverwriteAlways && overwrite != transfer.OverwriteNever {
+			return 4, fmt.Errorf("при загрузке из stdin из политик перезаписи работает только --no-clobber")
+		}
+		rec := transferRecord{Command: "put", Bucket: sp.Bucket, Key: sp.Key, Local: "-", Total: 1}
+		if err := transfer.UploadStream(ctx, client.S3, sp.Bucket, sp.Key, os.Stdin, opts); err != nil {
+			if errors.Is(err, transfer.ErrSkipped) {
+				rec.Status, rec.Skipped = "skipped", 1
+				printDone(fmt.Sprintf("Пропущено: s3://%s/%s уже существует.", sp.Bucket, sp.Key), rec)
+				return 0, nil
+			}
+			return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+		}
+		rec.Status, rec.Transferred = "ok", 1
+		printDone("Загружено.", rec)
+		return 0, nil
+	}
+	if partSize > 0 {
+		return 4, fmt.Errorf("--part-size задаётся только для загрузки из stdin (put - ...)")
+	}
+
 	info, err := os.Stat(localPath)
 	if err != nil {
 		return 1, fmt.Errorf("не удалось получить информацию о %q: %w", localPath, err)
 	}
 
 	if info.IsDir() {
+		if err := flt.LoadIgnoreFile(filepath.Join(localPath, filter.IgnoreFile)); err != nil {
+			return 1, err
+		}
 		prefix := sp.Key
 		if prefix == "" || !strings.HasSuffix(prefix, "/") {
 			prefix += "/"
 		}
-		stats, err := transfer.UploadTree(ctx, client.S3, sp.Bucket, prefix, localPath, jobs, showProgress)
+		stats, err := transfer.UploadTree(ctx, client.S3, sp.Bucket, prefix, localPath, opts)
 		if err != nil {
 			return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
 		}
-		fmt.Printf("Файлов: %d, загружено: %d, ошибок: %d\n", stats.TotalFiles, stats.Uploaded, stats.Failed)
-		if stats.Failed > 0 {
-			return 1, fmt.Errorf("почти... часть файлов не загружена")
-		}
-		return 0, nil
+		return putSummary(stats, sp, reportPath)
 	}
 
 	key := sp.Key
@@ -589,25 +1114,80 @@ func runPut(args []string, cfgPath string, verbose bool, showProgress bool) (int
 			key = base
 		}
 	}
-	if err := transfer.UploadFile(ctx, client.S3, sp.Bucket, key, localPath, showProgress); err != nil {
+	rec := transferRecord{Command: "put", Bucket: sp.Bucket, Key: key, Local: localPath, Total: 1}
+	if err := transfer.UploadFile(ctx, client.S3, sp.Bucket, key, localPath, opts); err != nil {
+		if errors.Is(err, transfer.ErrSkipped) {
+			rec.Status, rec.Skipped = "skipped", 1
+			printDone(fmt.Sprintf("Пропущено: s3://%s/%s уже существует.", sp.Bucket, key), rec)
+			return 0, nil
+		}
 		return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
 	}
-	fmt.Println("Загружено.")
+	rec.Status, rec.Transferred = "ok", 1
+	printDone("Загружено.", rec)
 	return 0, nil
 }
 
-func runGet(args []string, cfgPath string, verbose bool, showProgress bool) (int, error) {
-	// get <alias>/<bucket>/<key|prefix/> <local_path> [-j N]
-	if len(args) < 2 {
-		return 4, fmt.Errorf("нужно указать источник alias/bucket/key|prefix/ и локальный путь\n\n%s", getUsage())
+func putSummary(stats transfer.PutStats, sp s3Path, reportPath string) (int, error) {
+	if reportPath != "" {
+		if err := writeReport(reportPath, newReport("put", sp, stats.TotalFiles, stats.FailedItems)); err != nil {
+			return 1, err
+		}
 	}
+	if structured() {
+		rec := transferRecord{
+			Command:     "put",
+			Bucket:      sp.Bucket,
+			Key:         sp.Key,
+			Total:       stats.TotalFiles,
+			Transferred: stats.Uploaded,
+			Skipped:     stats.Skipped,
+			Failed:      stats.Failed,
+			FailedItems: reportItems(stats.FailedItems),
+			Warnings:    stats.Warnings,
+		}
+		rec.Status = rec.status()
+		emitRecord(rec, transferCols...)
+		// всё уже в записи — отдельная запись об ошибке сделала бы вывод не одним документом
+		if stats.Failed > 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	printWarnings(stats.Warnings)
+	printFailed(stats.FailedItems)
+	fmt.Printf("Файлов: %d, загружено: %d, пропущено: %d, ошибок: %d\n", stats.TotalFiles, stats.Uploaded, stats.Skipped, stats.Failed)
+	if stats.Failed > 0 {
+		return 1, fmt.Errorf("почти... часть файлов не загружена")
+	}
+	return 0, nil
+}
 
-	source := args[0]
-	localRoot := args[1]
+func runGet(args []string, cfgPath string, verbose bool, showProgress bool, limitRate int64) (int, error) {
+	// get <alias>/<bucket>/<key|prefix/> <local_path> [-j N] [--continue] [--report FILE]
+	// get --retry-from FILE [-j N] [--report FILE]
 	jobs := 4
-
-	for i := 2; i < len(args); i++ {
+	retries := -1
+	cont := false
+	sanitize := false
+	conflict := transfer.ConflictFail
+	reportPath := ""
+	retryFrom := ""
+	overwrite := transfer.OverwriteAlways
+	flt := filter.New()
+	preserve, preserveOwner := false, false
+	links := transfer.LinksDefault
+	versionID := ""
+	var pos []string
+
+	for i := 0; i < len(args); i++ {
 		switch args[i] {
+		case "--version-id":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --version-id требует значение")
+			}
+			versionID = args[i+1]
+			i++
 		case "-j", "--jobs":
 			if i+1 >= len(args) {
 				return 4, fmt.Errorf("флаг -j требует число")
@@ -618,20 +1198,126 @@ func runGet(args []string, cfgPath string, verbose bool, showProgress bool) (int
 			}
 			jobs = n
 			i++
+		case "-c", "--continue":
+			cont = true
+		case "--sanitize":
+			sanitize = true
+		case "--conflict":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --conflict требует значение: skip, suffix или fail")
+			}
+			switch args[i+1] {
+			case "fail":
+				conflict = transfer.ConflictFail
+			case "skip":
+				conflict = transfer.ConflictSkip
+			case "suffix":
+				conflict = transfer.ConflictSuffix
+			default:
+				return 4, fmt.Errorf("некорректное значение для --conflict: %q (ожидаю skip, suffix или fail)", args[i+1])
+			}
+			i++
+		case "--retries":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --retries требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				return 4, fmt.Errorf("некорректное значение для --retries: %q", args[i+1])
+			}
+			retries = n
+			i++
+		case "--report":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --report требует путь к файлу")
+			}
+			reportPath = args[i+1]
+			i++
+		case "--retry-from":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --retry-from требует путь к файлу отчёта")
+			}
+			retryFrom = args[i+1]
+			i++
+		case "--no-clobber", "--update", "--skip-existing-same-size":
+			if err := setOverwrite(&overwrite, args[i]); err != nil {
+				return 4, err
+			}
+		case "--include", "--exclude":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг %s требует шаблон", args[i])
+			}
+			if err := addFilter(flt, args[i], args[i+1]); err != nil {
+				return 4, err
+			}
+			i++
+		case "--preserve":
+			preserve = true
+		case "--preserve-owner":
+			preserve, preserveOwner = true, true
+		case "--links":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --links требует значение: store")
+			}
+			if err := setLinks(&links, args[i+1], "store"); err != nil {
+				return 4, err
+			}
+			i++
 		case "-h", "--help":
 			fmt.Print(getUsage())
 			return 0, nil
 		default:
-			return 4, fmt.Errorf("неизвестный аргумент для get: %q\n\n%s", args[i], getUsage())
+			if v, ok := strings.CutPrefix(args[i], "--links="); ok {
+				if err := setLinks(&links, v, "store"); err != nil {
+					return 4, err
+				}
+				continue
+			}
+			if strings.HasPrefix(args[i], "-") {
+				return 4, fmt.Errorf("неизвестный аргумент для get: %q\n\n%s", args[i], getUsage())
+			}
+			pos = append(pos, args[i])
 		}
 	}
 
-	sp, err := parseS3Path(source)
-	if err != nil {
-		return 4, err
-	}
-	if sp.Key == "" {
-		return 4, fmt.Errorf("нужно указать ключ или префикс для скачивания")
+	var (
+		sp        s3Path
+		rep       *transferReport
+		localRoot string
+	)
+	if retryFrom != "" {
+		if len(pos) != 0 {
+			return 4, fmt.Errorf("с --retry-from пути берутся из отчёта, лишний аргумент: %q", pos[0])
+		}
+		if versionID != "" {
+			return 4, fmt.Errorf("--version-id и --retry-from вместе не работают")
+		}
+		r, err := readReport(retryFrom, "get")
+		if err != nil {
+			return 4, err
+		}
+		if len(r.Failed) == 0 {
+			printDone("В отчёте нет нескачанных файлов.", transferRecord{Command: "get", Status: "ok", Bucket: r.Bucket})
+			return 0, nil
+		}
+		rep = &r
+		sp = s3Path{Alias: r.Alias, Bucket: r.Bucket}
+	} else {
+		if len(pos) != 2 {
+			return 4, fmt.Errorf("нужно указать источник alias/bucket/key|prefix/ и локальный путь\n\n%s", getUsage())
+		}
+		localRoot = pos[1]
+		var err error
+		sp, err = parseS3Path(pos[0])
+		if err != nil {
+			return 4, err
+		}
+		if sp.Key == "" {
+			return 4, fmt.Errorf("нужно указать ключ или префикс для скачивания")
+		}
+		if versionID != "" && strings.HasSuffix(sp.Key, "/") {
+			return 4, fmt.Errorf("--version-id работает только для одного объекта, не для префикса")
+		}
 	}
 
 	cfg, err := config.Load(cfgPath)
@@ -646,7 +1332,7 @@ func runGet(args []string, cfgPath string, verbose bool, showProgress bool) (int
 		return 1, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 24*time.Hour)
+	ctx, cancel := context.WithTimeout(rootCtx, 24*time.Hour)
 	defer cancel()
 
 	client, err := s3client.New(ctx, alias)
@@ -654,39 +1340,158 @@ func runGet(args []string, cfgPath string, verbose bool, showProgress bool) (int
 		return 1, err
 	}
 
+	limiter, err := rateLimiter(limitRate, alias)
+	if err != nil {
+		return 1, err
+	}
+	opts := transfer.GetOptions{
+		Jobs:          jobs,
+		ShowProgress:  showProgress,
+		Continue:      cont,
+		Retry:         retryPolicy(retries, alias),
+		Limiter:       limiter,
+		Overwrite:     overwrite,
+		Filter:        flt,
+		Sanitize:      sanitize,
+		Conflict:      conflict,
+		Preserve:      preserve,
+		PreserveOwner: preserveOwner,
+		Links:         links,
+		VersionID:     versionID,
+	}
+
+	if rep != nil {
+		items := make([]transfer.DownloadItem, 0, len(rep.Failed))
+		for _, it := range rep.Failed {
+			items = append(items, transfer.DownloadItem{Key: it.Key, Local: it.Local})
+		}
+		stats, err := transfer.DownloadItems(ctx, client.S3, sp.Bucket, items, opts)
+		if err != nil {
+			return 1, err
+		}
+		return getSummary(stats, sp, reportPath)
+	}
+
 	if strings.HasSuffix(sp.Key, "/") {
-		keys, err := client.ListAllKeys(ctx, sp.Bucket, sp.Key)
+		objs, err := client.ListAllObjects(ctx, sp.Bucket, sp.Key)
 		if err != nil {
 			return handleAWSError(err, verbose, "не найдено", "Доступ запрещён")
 		}
-		if len(keys) == 0 {
+		if len(objs) == 0 {
+			if structured() {
+				return 2, fmt.Errorf("под префиксом s3://%s/%s объектов не найдено", sp.Bucket, sp.Key)
+			}
 			fmt.Println("Под данным префиксом объектов не найдено!")
 			return 2, nil
 		}
-		stats, err := transfer.DownloadKeys(ctx, client.S3, sp.Bucket, keys, sp.Key, localRoot, jobs, showProgress)
+		stats, err := transfer.DownloadObjects(ctx, client.S3, sp.Bucket, objs, sp.Key, localRoot, opts)
 		if err != nil {
 			return 1, err
 		}
-		fmt.Printf("Файлов: %d, скачано: %d, ошибок: %d\n", stats.TotalFiles, stats.Downloaded, stats.Failed)
-		if stats.Failed > 0 {
-			return 1, fmt.Errorf("ну почти... часть файлов не скачана")
-		}
-		return 0, nil
+		return getSummary(stats, sp, reportPath)
 	}
 
 	dest := localRoot
 	fi, err := os.Stat(localRoot)
 	if err == nil && fi.IsDir() {
-		base := filepath.Base(sp.Key)
-		dest = filepath.Join(localRoot, base)
+		dest, err = transfer.LocalPathFor(localRoot, sp.Key, sanitize)
+		if err != nil {
+			return 1, err
+		}
 	}
-	if err := transfer.DownloadFile(ctx, client.S3, sp.Bucket, sp.Key, dest, showProgress); err != nil {
+	rec := transferRecord{Command: "get", Bucket: sp.Bucket, Key: sp.Key, Local: dest, Total: 1}
+	if err := transfer.DownloadFile(ctx, client.S3, sp.Bucket, sp.Key, dest, opts); err != nil {
+		if errors.Is(err, transfer.ErrSkipped) {
+			rec.Status, rec.Skipped = "skipped", 1
+			printDone(fmt.Sprintf("Пропущено: %s уже существует.", dest), rec)
+			return 0, nil
+		}
+		if errors.Is(err, transfer.ErrSymlink) || errors.Is(err, transfer.ErrUnsafeKey) {
+			return 1, err
+		}
+		if versionID != "" {
+			return handleAWSError(err, verbose, fmt.Sprintf("Версия не найдена: %s/%s (%s)", sp.Bucket, sp.Key, versionID), "Доступ запрещён")
+		}
 		return handleAWSError(err, verbose, "не найдено", "Доступ запрещён")
 	}
-	fmt.Println("Скачано.")
+	rec.Status, rec.Transferred = "ok", 1
+	printDone("Скачано.", rec)
 	return 0, nil
 }
 
+func getSummary(stats transfer.GetStats, sp s3Path, reportPath string) (int, error) {
+	if reportPath != "" {
+		rep := newReport("get", sp, stats.TotalFiles, stats.FailedItems)
+		rep.Rejected = reportItems(stats.Rejected)
+		if err := writeReport(reportPath, rep); err != nil {
+			return 1, err
+		}
+	}
+	if structured() {
+		rec := transferRecord{
+			Command:     "get",
+			Bucket:      sp.Bucket,
+			Key:         sp.Key,
+			Total:       stats.TotalFiles,
+			Transferred: stats.Downloaded,
+			Dirs:        stats.Dirs,
+			Skipped:     stats.Skipped,
+			Failed:      stats.Failed,
+			FailedItems: reportItems(stats.FailedItems),
+			Rejected:    reportItems(stats.Rejected),
+			Warnings:    stats.Warnings,
+		}
+		for _, c := range stats.Conflicts {
+			rec.Warnings = append(rec.Warnings, c.Err.Error())
+		}
+		rec.Status = rec.status()
+		emitRecord(rec, transferCols...)
+		if rec.Status != "ok" {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	printWarnings(stats.Warnings)
+	printFailed(stats.FailedItems)
+	printRejected(stats.Rejected)
+	printConflicts(stats.Conflicts)
+	fmt.Printf("Файлов: %d, скачано: %d, пропущено: %d, отклонено: %d, ошибок: %d\n",
+		stats.TotalFiles, stats.Downloaded, stats.Skipped, len(stats.Rejected), stats.Failed)
+	if stats.Dirs > 0 {
+		fmt.Printf("Каталогов по маркерам \"folder/\": %d\n", stats.Dirs)
+	}
+	if stats.Failed > 0 {
+		return 1, fmt.Errorf("ну почти... часть файлов не скачана")
+	}
+	if len(stats.Rejected) > 0 {
+		return 1, fmt.Errorf("часть ключей отклонена как небезопасные, см. --sanitize")
+	}
+	return 0, nil
+}
+
+// presignRecord — ссылка presign для --output
+type presignRecord struct {
+	Method    string    `json:"method"`
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func printPresigned(mode string, sp s3Path, url string, expire time.Duration) {
+	if !structured() {
+		fmt.Println(url)
+		return
+	}
+	emitRecord(presignRecord{
+		Method:    strings.ToUpper(mode),
+		Bucket:    sp.Bucket,
+		Key:       sp.Key,
+		URL:       url,
+		ExpiresAt: time.Now().Add(expire).UTC().Truncate(time.Second),
+	}, "method", "bucket", "key", "url", "expires_at")
+}
+
 func runPresign(args []string, cfgPath string, verbose bool) (int, error) {
 	// presign get <alias>/<bucket>/<key> [--expire 15m]
 	// presign put <alias>/<bucket>/<key> [--expire 15m] [--content-type text/plain]
@@ -750,7 +1555,7 @@ func runPresign(args []string, cfgPath string, verbose bool) (int, error) {
 		return 1, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(rootCtx, 30*time.Second)
 	defer cancel()
 
 	client, err := s3client.New(ctx, alias)
@@ -764,21 +1569,21 @@ func runPresign(args []string, cfgPath string, verbose bool) (int, error) {
 		if err != nil {
 			return handleAWSError(err, verbose, "Не удалось сформировать ссылку", "Доступ запрещён")
 		}
-		fmt.Println(url)
+		printPresigned(mode, sp, url, expire)
 		return 0, nil
 	case "put":
 		url, err := client.PresignPut(ctx, sp.Bucket, sp.Key, contentType, expire)
 		if err != nil {
 			return handleAWSError(err, verbose, "Не удалось сформировать ссылку", "Доступ запрещён")
 		}
-		fmt.Println(url)
+		printPresigned(mode, sp, url, expire)
 		return 0, nil
 	default:
 		return 4, fmt.Errorf("неизвестная подкоманда presign: %q\n\n%s", mode, presignUsage())
 	}
 }
 
-func parseGlobalFlags(argv []string, cfgPath *string, verbose *bool, noProgress *bool) ([]string, error) {
+func parseGlobalFlags(argv []string, cfgPath *string, verbose *bool, noProgress *bool, limitRate *int64, format *outputFormat) ([]string, error) {
 	out := make([]string, 0, len(argv))
 	for i := 0; i < len(argv); i++ {
 		switch argv[i] {
@@ -792,6 +1597,28 @@ func parseGlobalFlags(argv []string, cfgPath *string, verbose *bool, noProgress
 			*verbose = true
 		case "--no-progress":
 			*noProgress = true
+		case "--limit-rate":
+			if i+1 >= len(argv) {
+				return nil, fmt.Errorf("флаг --limit-rate требует значение, пример: 20MiB/s")
+			}
+			n, err := parseRate(argv[i+1])
+			if err != nil {
+				return nil, err
+			}
+			*limitRate = n
+			i++
+		case "--json":
+			*format = outputJSON
+		case "--output":
+			if i+1 >= len(argv) {
+				return nil, fmt.Errorf("флаг --output требует значение: json, jsonl, table или csv")
+			}
+			f, err := parseOutput(argv[i+1])
+			if err != nil {
+				return nil, err
+			}
+			*format = f
+			i++
 		case "-h", "--help":
 			out = append(out, argv[i])
 		default:
@@ -808,23 +1635,60 @@ func usageShort() string {
 	b.WriteString("Использование:\n")
 	b.WriteString("  s3cli [глобальные флаги] <команда> [аргументы]\n\n")
 	b.WriteString("Команды:\n")
-	b.WriteString("  alias add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style]\n")
+	b.WriteString("  alias add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style] [--retries N] [--limit-rate RATE]\n")
 	b.WriteString("  alias ls\n")
 	b.WriteString("  alias rm <name>\n\n")
-	b.WriteString("  ls <alias>/<bucket>/<prefix?>\n\n")
-	b.WriteString("  put put <local_path> <alias>/<bucket>/<key|prefix/> [-j N]\n\n")
-	b.WriteString("  get <alias>/<bucket>/<key|prefix/> <local_path> [-j N]\n\n")
+	b.WriteString("  ls <alias>\n")
+	b.WriteString("  ls <alias>/<bucket>/<prefix?> [-r [--max-depth N]] [--include GLOB] [--exclude GLOB] [--versions]\n")
+	b.WriteString("  tree <alias>/<bucket>/<prefix?> [--max-depth N]\n")
+	b.WriteString("  du <alias>/<bucket>/<prefix?> [--depth N] [--all-versions] [-j N]\n")
+	b.WriteString("  find <alias>/<bucket>/<prefix?> [--name GLOB] [--newer T] [--older T] [--larger SIZE] [--smaller SIZE]\n")
+	b.WriteString("       [--exec CMD | --delete | --print0]\n\n")
+	b.WriteString("  mb <alias>/<bucket> [--region R] [--with-lock]\n")
+	b.WriteString("  rb <alias>/<bucket> [--force]\n")
+	b.WriteString("  version enable|suspend|info <alias>/<bucket>\n")
+	b.WriteString("  undo-rm <alias>/<bucket>/<prefix?> [--since T] [--dry-run]\n")
+	b.WriteString("  restore-to <alias>/<bucket>/<prefix?> --at T [--delete] [--dry-run] [-j N]\n")
+	b.WriteString("  multipart ls <alias>/<bucket>/<prefix?> [--older-than T]\n")
+	b.WriteString("  multipart abort <alias>/<bucket>/<key> --upload-id ID\n")
+	b.WriteString("  multipart abort <alias>/<bucket>/<prefix?> --older-than T | --all [--dry-run]\n")
+	b.WriteString("  lifecycle get|rm <alias>/<bucket>\n")
+	b.WriteString("  lifecycle set <alias>/<bucket> <file.yaml|->\n\n")
+	b.WriteString("  put <local_path> <alias>/<bucket>/<key|prefix/> [-j N] [--retries N] [--no-resume] [--report FILE]\n")
+	b.WriteString("  put --retry-from FILE [-j N]\n")
+	b.WriteString("  put - <alias>/<bucket>/<key> [--part-size SIZE]\n\n")
+	b.WriteString("  get <alias>/<bucket>/<key|prefix/> <local_path> [-j N] [--retries N] [--continue] [--report FILE]\n")
+	b.WriteString("  get <alias>/<bucket>/<key> <local_path> --version-id ID\n")
+	b.WriteString("  get --retry-from FILE [-j N]\n\n")
+	b.WriteString("  sync <src> <dst> [-j N] [--retries N] [--delete] [--dry-run] [--compare mtime|etag]\n\n")
+	b.WriteString("  cp [-r] <alias>/<bucket>/<key|prefix/> <alias>/<bucket>/<key|prefix/> [-j N] [--retries N]\n")
+	b.WriteString("  mv [-r] <alias>/<bucket>/<key|prefix/> <alias>/<bucket>/<key|prefix/> [-j N] [--retries N]\n\n")
 	b.WriteString("  presign get <alias>/<bucket>/<key> [--expire 15m]\n")
 	b.WriteString("  presign put <alias>/<bucket>/<key> [--expire 15m] [--content-type ...]\n")
 	b.WriteString("Глобальные флаги:\n")
 	b.WriteString("  --config PATH      Конфиг -> (по умолчанию ~/.s3cli/config.yaml)\n")
 	b.WriteString("  --no-progress      Отключить прогресс-бар\n")
+	b.WriteString("  --limit-rate RATE  Ограничить скорость put/get/cp/sync, например 20MiB/s\n")
+	b.WriteString("  --output FORMAT    Формат вывода: table (по умолчанию), json, jsonl или csv\n")
+	b.WriteString("  --json             То же, что --output json\n")
 	b.WriteString("  -v, --verbose      Подробные ошибки/детали\n")
 	b.WriteString("  -h, --help         Справка\n")
+	b.WriteString("\nМашинно-читаемый вывод (--output json|jsonl|csv):\n")
+	b.WriteString("  листинги (ls, tree, find, du, alias ls, multipart ls, lifecycle get) — записи с полями на английском, json — массив,\n")
+	b.WriteString("  jsonl — по записи на строку; stat, put/get/cp/mv/sync, rm, mb/rb, presign,\n")
+	b.WriteString("  undo-rm, restore-to, multipart abort, lifecycle set/rm — одна запись-итог (с --dry-run — план записями).\n")
+	b.WriteString("  Ошибка в json/jsonl — запись {\"error\": {\"message\", \"exit_code\", \"http_status\", \"code\"}}\n")
+	b.WriteString("  в stdout; код выхода тот же, прогресс-бар отключается. Если листинг оборвался на середине,\n")
+	b.WriteString("  в json эта запись — последний элемент массива: документ в stdout всегда один.\n")
 	return b.String()
 }
 
 func handleAWSError(err error, verbose bool, notFoundMsg, forbiddenMsg string) (int, error) {
+	code, msg := awsErrorMessage(err, verbose, notFoundMsg, forbiddenMsg)
+	return code, withStatus(msg, err)
+}
+
+func awsErrorMessage(err error, verbose bool, notFoundMsg, forbiddenMsg string) (int, error) {
 	var re *smithyhttp.ResponseError
 	if errors.As(err, &re) {
 		status := re.HTTPStatusCode()
@@ -877,7 +1741,7 @@ func aliasUsage() string {
   s3cli alias <подкоманда> [аргументы]
 
 Подкоманды:
-  add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style]
+  add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style] [--retries N] [--limit-rate RATE]
   ls
   rm <name>
 
@@ -890,10 +1754,12 @@ func aliasUsage() string {
 
 func aliasAddUsage() string {
 	return `Использование:
-  s3cli alias add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style]
+  s3cli alias add <name> <endpoint> <access_key> <secret_key> [--region EU] [--ssl] [--path-style] [--retries N] [--limit-rate RATE]
 
 Описание:
   Добавляет алиас подключения к S3-совместимому хранилищу.
+  --retries N — сколько раз по умолчанию повторять файл при временных ошибках.
+  --limit-rate RATE — ограничение скорости передачи для алиаса, например 20MiB/s.
 `
 }
 
@@ -917,60 +1783,152 @@ func aliasRmUsage() string {
 
 func lsUsage() string {
 	return `Использование:
-  s3cli ls <alias>/<bucket>/<prefix?>
+  s3cli ls <alias>
+  s3cli ls <alias>/<bucket>/<prefix?> [-r [--max-depth N]] [--include GLOB] [--exclude GLOB]
+  s3cli ls <alias>/<bucket>/<prefix?> --versions [-r]
 
 Описание:
-  Показывает префиксы и объекты одним уровнем глубины.
+  Показывает префиксы и объекты одним уровнем глубины; с одним алиасом — список
+  бакетов с датами создания.
+  -r, --recursive — все объекты под префиксом с путями от него; строки выводятся
+  по мере листинга, без сортировки в памяти (S3 и так отдаёт ключи по возрастанию).
+  --max-depth N — вместе с -r: не глубже N уровней, более глубокие «папки»
+  показываются одной строкой. Иерархию с итогами по папкам рисует s3cli tree.
+  --include GLOB / --exclude GLOB — фильтры по пути относительно префикса,
+  повторяемые (подробнее: s3cli put --help).
+  --versions — все версии объектов и маркеры удаления с их VersionId, от новой
+  к старой; * отмечает текущую версию (управление: s3cli version --help).
 Пример:
   s3cli ls s3s7/fao_qa/reports/2025/
 `
 }
 func statUsage() string {
 	return `Использование:
-  s3cli stat <alias>/<bucket>/<key>
+  s3cli stat <alias>/<bucket>/<key> [--version-id ID]
 
 Описание:
   Показывает метаданные объекта.
+  --version-id ID — метаданные этой версии, а не текущей (ID — из ls --versions).
 `
 }
 
 func catUsage() string {
 	return `Использование:
-  s3cli cat <alias>/<bucket>/<key>
+  s3cli cat <alias>/<bucket>/<key> [--version-id ID]
 
 Описание:
   Выводит содержимое объекта в stdout.
+  --version-id ID — вывести эту версию, а не текущую (ID — из ls --versions).
 `
 }
 
 func rmUsage() string {
 	return `Использование:
   s3cli rm <alias>/<bucket>/<key>
-  s3cli rm -r <alias>/<bucket>/<prefix/>
+  s3cli rm -r <alias>/<bucket>/<prefix/> [--include GLOB] [--exclude GLOB]
+  s3cli rm <alias>/<bucket>/<key> --version-id ID
 
 Описание:
   Удаляет объект или все объекты под заданным префиксом (-r)
+  В бакете с версионированием обычное удаление лишь ставит маркер удаления,
+  прежние версии остаются. --version-id ID — удалить навсегда одну версию
+  (или маркер удаления — тогда объект снова появится).
+  --include GLOB / --exclude GLOB — фильтры по пути относительно префикса,
+  повторяемые (подробнее: s3cli put --help).
   Чтобы "не натворить дел" пустой префикс не допускается!
 `
 }
 
 func getUsage() string {
 	return `Использование:
-  s3cli get <alias>/<bucket>/<key|prefix/> <local_path> [-j N]
+  s3cli get <alias>/<bucket>/<key|prefix/> <local_path> [-j N] [--retries N] [--continue]
+            [--no-clobber|--update|--skip-existing-same-size]
+            [--include GLOB] [--exclude GLOB] [--sanitize] [--conflict skip|suffix|fail]
+            [--preserve] [--preserve-owner] [--links=store] [--report FILE]
+  s3cli get <alias>/<bucket>/<key> <local_path> --version-id ID
+  s3cli get --retry-from FILE [-j N] [--report FILE]
 
 Описание:
   Скачивает один объект или весь префикс (рекурсивно) по указанному пути
   -j N — число параллельных загрузок (по умолчанию 4).
+  --retries N — сколько раз повторять файл при временных ошибках
+  (5xx, SlowDown, таймауты, обрыв соединения). По умолчанию 3 или значение из алиаса.
+  Файлы сначала пишутся во временный файл рядом и переименовываются на место
+  только после успешного скачивания.
+  -c, --continue — докачать частично скачанный файл. Недокачанные данные и состояние
+  хранятся рядом (.<имя>.s3cli-partial, .<имя>.s3cli-state) и при сбое не удаляются;
+  если объект с тех пор изменился, скачивание начнётся заново.
+  Если локальный файл уже есть (по умолчанию он перезаписывается):
+  --no-clobber — не перезаписывать;
+  --update — скачать, только если объект новее файла;
+  --skip-existing-same-size — пропустить, если размер совпадает.
+  --include GLOB / --exclude GLOB — фильтры по пути относительно префикса,
+  повторяемые (подробнее: s3cli put --help).
+  Ключи, которые вывели бы файл за пределы локального каталога (абсолютные пути,
+  сегменты "..", NUL), не скачиваются и выводятся списком.
+  --sanitize — вместо отказа переписать такие ключи: ведущий "/" отбрасывается,
+  ".." заменяется на "__", NUL — на "_".
+  Ключи-маркеры вида "folder/" (их создаёт консоль AWS) становятся каталогами.
+  Если одно имя нужно и как файл, и как каталог (в бакете есть "a" и "a/b",
+  или на диске уже лежит не то), это выясняется до начала скачивания:
+  --conflict fail — ничего не качать и перечислить конфликты (по умолчанию);
+  --conflict skip — пропустить то, что не помещается;
+  --conflict suffix — сохранить под свободным именем вида "a (1)".
+  --preserve — восстановить время изменения и права из метаданных объекта
+  (put --preserve, rclone или mc); --preserve-owner — ещё и владельца (нужны права root).
+  --links=store — создавать символические ссылки, сохранённые put --links=store
+  (ссылки, ведущие за пределы локального каталога, не создаются). Без флага такие
+  объекты пропускаются с предупреждением.
+  --report FILE — записать в FILE (JSON) список нескачанных файлов с ошибками.
+  --retry-from FILE — повторить только файлы из такого отчёта.
+  --version-id ID — скачать эту версию объекта, а не текущую (ID — из ls --versions);
+  только для одного объекта.
 `
 }
 
 func putUsage() string {
 	return `Использование:
-  s3cli put <local_path> <alias>/<bucket>/<key|prefix/> [-j N]
+  s3cli put <local_path> <alias>/<bucket>/<key|prefix/> [-j N] [--retries N] [--no-resume]
+            [--no-clobber|--update|--skip-existing-same-size]
+            [--include GLOB] [--exclude GLOB] [--preserve] [--preserve-owner]
+            [--follow-symlinks|--links=store] [--report FILE]
+  s3cli put --retry-from FILE [-j N] [--report FILE]
+  s3cli put - <alias>/<bucket>/<key> [--part-size SIZE] [--no-clobber]
 
 Описание:
   Загрузка файла или всего каталога (рекурсивно) в указанный бакет/префикс.
+  "-" вместо пути — читать stdin (например, pg_dump | s3cli put - prod/backups/db.sql).
+  Размер заранее неизвестен, поток грузится частями по --part-size (по умолчанию 16MiB);
+  частей не больше 10000, поэтому для потоков больше ~150 ГиБ увеличьте его, например
+  --part-size 64MiB (до ~640 ГиБ). Каждая из параллельных частей держится в памяти.
   -j N — число параллельных загрузок (по умолчанию 4).
+  --retries N — сколько раз повторять файл при временных ошибках
+  (5xx, SlowDown, таймауты, обрыв соединения). По умолчанию 3 или значение из алиаса.
+  Большие файлы грузятся частями, состояние пишется в ~/.s3cli/uploads/,
+  повторный запуск того же put докачивает только недостающие части.
+  --no-resume — не докачивать, начать загрузку заново.
+  Если объект уже есть (по умолчанию он перезаписывается):
+  --no-clobber — не перезаписывать;
+  --update — загрузить, только если файл новее объекта;
+  --skip-existing-same-size — пропустить, если размер совпадает.
+  --include GLOB / --exclude GLOB — какие файлы каталога грузить; флаги повторяемые,
+  из совпавших побеждает последний. Если есть хоть один --include, грузится только
+  то, что под него подходит. Путь берётся относительно каталога, через "/":
+  * — любые символы кроме "/", ** — любое число каталогов, шаблон без "/" ищется
+  на любой глубине, шаблон на "/" — каталог целиком (node_modules/).
+  Файл .s3ignore в корне каталога — те же шаблоны по строке (# — комментарий,
+  !шаблон — вернуть исключённое); флаги важнее него.
+  --preserve — сохранить время изменения и права файла в метаданных объекта
+  (x-amz-meta-mtime, x-amz-meta-mode, как rclone, и x-amz-meta-mc-attrs, как mc).
+  --preserve-owner — то же плюс uid/gid владельца.
+  Символические ссылки в каталоге: ссылки на файлы грузятся как сами файлы,
+  ссылки на каталоги пропускаются с предупреждением.
+  --follow-symlinks (--links=follow) — заходить и в ссылки на каталоги; ссылка
+  на собственный родительский каталог (цикл) пропускается с предупреждением.
+  --links=store — сохранить саму ссылку: пустой объект с целью в x-amz-meta-s3cli-symlink,
+  get --links=store создаст её обратно.
+  --report FILE — записать в FILE (JSON) список незагруженных файлов с ошибками.
+  --retry-from FILE — повторить только файлы из такого отчёта.
 `
 }
 
@@ -985,4 +1943,3 @@ func presignUsage() string {
   --content-type — заголовок Content-Type для PUT (если нужен).
 `
 }
-
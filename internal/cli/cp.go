@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+	"github.com/wolfsTail/s3cli/internal/transfer"
+)
+
+func runCp(args []string, cfgPath string, verbose bool, showProgress bool, limitRate int64, move bool) (int, error) {
+	// cp [-r] <alias>/<bucket>/<key|prefix/> <alias>/<bucket>/<key|prefix/> [-j N]
+	// mv [-r] ...
+	usage := cpUsage
+	cmd := "cp"
+	if move {
+		usage = mvUsage
+		cmd = "mv"
+	}
+
+	recursive := false
+	jobs := 4
+	retries := -1
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-r", "--recursive":
+			recursive = true
+		case "-j", "--jobs":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг -j требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return 4, fmt.Errorf("некорректное значение для -j: %q", args[i+1])
+			}
+			jobs = n
+			i++
+		case "--retries":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --retries требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				return 4, fmt.Errorf("некорректное значение для --retries: %q", args[i+1])
+			}
+			retries = n
+			i++
+		case "-h", "--help":
+			fmt.Print(usage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return 4, fmt.Errorf("неизвестный флаг для %s: %q\n\n%s", cmd, args[i], usage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) != 2 {
+		return 4, fmt.Errorf("нужно указать источник и приёмник вида alias/bucket/key\n\n%s", usage())
+	}
+
+	src, err := parseS3Path(pos[0])
+	if err != nil {
+		return 4, err
+	}
+	dst, err := parseS3Path(pos[1])
+	if err != nil {
+		return 4, err
+	}
+	if src.Key == "" {
+		return 4, fmt.Errorf("нужно указать ключ или префикс источника, а не только алиас/бакет")
+	}
+
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+	srcAlias, err := cfg.GetAlias(src.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден", src.Alias)
+		}
+		return 1, err
+	}
+	dstAlias, err := cfg.GetAlias(dst.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден", dst.Alias)
+		}
+		return 1, err
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 24*time.Hour)
+	defer cancel()
+
+	client, err := s3client.New(ctx, srcAlias)
+	if err != nil {
+		return 1, err
+	}
+	// разные алиасы — разные endpoint'ы и ключи, серверное копирование невозможно
+	dstClient := client
+	if dst.Alias != src.Alias {
+		dstClient, err = s3client.New(ctx, dstAlias)
+		if err != nil {
+			return 1, err
+		}
+	}
+
+	limiter, err := rateLimiter(limitRate, srcAlias)
+	if err != nil {
+		return 1, err
+	}
+	opts := transfer.CopyOptions{
+		Jobs:         jobs,
+		ShowProgress: showProgress,
+		Move:         move,
+		Retry:        retryPolicy(retries, srcAlias),
+		Limiter:      limiter,
+	}
+
+	if recursive {
+		srcPrefix := src.Key
+		if !strings.HasSuffix(srcPrefix, "/") {
+			srcPrefix += "/"
+		}
+		dstPrefix := dst.Key
+		if dstPrefix != "" && !strings.HasSuffix(dstPrefix, "/") {
+			dstPrefix += "/"
+		}
+		if src.Alias == dst.Alias && src.Bucket == dst.Bucket && srcPrefix == dstPrefix {
+			return 4, fmt.Errorf("источник и приёмник совпадают")
+		}
+		keys, err := client.ListAllKeys(ctx, src.Bucket, srcPrefix)
+		if err != nil {
+			return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+		}
+		if len(keys) == 0 {
+			if structured() {
+				return 2, fmt.Errorf("под префиксом s3://%s/%s объектов не найдено", src.Bucket, srcPrefix)
+			}
+			fmt.Println("Под данным префиксом объектов не найдено!")
+			return 2, nil
+		}
+		items := make([]transfer.CopyItem, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, transfer.CopyItem{SrcKey: k, DstKey: dstPrefix + strings.TrimPrefix(k, srcPrefix)})
+		}
+		stats, err := transfer.CopyKeys(ctx, client, dstClient, src.Bucket, dst.Bucket, items, opts)
+		if err != nil {
+			return 1, err
+		}
+		if structured() {
+			rec := transferRecord{
+				Command:     cmd,
+				Bucket:      src.Bucket,
+				Key:         srcPrefix,
+				Dest:        "s3://" + dst.Bucket + "/" + dstPrefix,
+				Total:       stats.TotalFiles,
+				Transferred: stats.Copied,
+				Failed:      stats.Failed,
+			}
+			rec.Status = rec.status()
+			emitRecord(rec, transferCols...)
+			if stats.Failed > 0 {
+				return 1, nil
+			}
+			return 0, nil
+		}
+		fmt.Printf("Объектов: %d, скопировано: %d, ошибок: %d\n", stats.TotalFiles, stats.Copied, stats.Failed)
+		if stats.Failed > 0 {
+			return 1, fmt.Errorf("часть объектов не скопирована")
+		}
+		return 0, nil
+	}
+
+	if strings.HasSuffix(src.Key, "/") {
+		return 4, fmt.Errorf("источник — префикс, для копирования префикса используйте флаг -r")
+	}
+	dstKey := dst.Key
+	if dstKey == "" || strings.HasSuffix(dstKey, "/") {
+		dstKey += path.Base(src.Key)
+	}
+
+	switch {
+	case dstClient != client:
+		err = transfer.StreamCopy(ctx, client.S3, dstClient.S3, src.Bucket, src.Key, dst.Bucket, dstKey, opts)
+	case move:
+		err = opts.Retry.Do(ctx, func() error {
+			return client.MoveObject(ctx, src.Bucket, src.Key, dst.Bucket, dstKey)
+		})
+	default:
+		err = opts.Retry.Do(ctx, func() error {
+			_, err := client.CopyObject(ctx, src.Bucket, src.Key, dst.Bucket, dstKey)
+			return err
+		})
+	}
+	if err != nil {
+		return handleAWSError(err, verbose,
+			fmt.Sprintf("Объект не найден: %s/%s", src.Bucket, src.Key),
+			"Доступ запрещён",
+		)
+	}
+	rec := transferRecord{Command: cmd, Status: "ok", Bucket: src.Bucket, Key: src.Key,
+		Dest: "s3://" + dst.Bucket + "/" + dstKey, Total: 1, Transferred: 1}
+	if move {
+		printDone("Перемещено.", rec)
+	} else {
+		printDone("Скопировано.", rec)
+	}
+	return 0, nil
+}
+
+func cpUsage() string {
+	return `Использование:
+  s3cli cp <alias>/<bucket>/<key> <alias>/<bucket>/<key|prefix/>
+  s3cli cp -r <alias>/<bucket>/<prefix/> <alias>/<bucket>/<prefix/> [-j N] [--retries N]
+
+Описание:
+  Копирует объект или весь префикс (-r) на стороне сервера, без скачивания.
+  Объекты больше 5 ГиБ копируются частями.
+  Если алиасы источника и приёмника разные, данные передаются потоком
+  из одного хранилища в другое (без записи на локальный диск).
+  -j N — число параллельных копирований (по умолчанию 4).
+  --retries N — сколько раз повторять объект при временных ошибках.
+
+Пример:
+  s3cli cp -r minio/data/2025/ aws/archive/2025/ -j 8
+`
+}
+
+func mvUsage() string {
+	return `Использование:
+  s3cli mv <alias>/<bucket>/<key> <alias>/<bucket>/<key|prefix/>
+  s3cli mv -r <alias>/<bucket>/<prefix/> <alias>/<bucket>/<prefix/> [-j N] [--retries N]
+
+Описание:
+  Перемещает (переименовывает) объект или весь префикс (-r) на стороне сервера.
+  Источник удаляется только после проверки копии (размер и ETag).
+  Между разными алиасами данные передаются потоком, как в cp.
+  -j N — число параллельных перемещений (по умолчанию 4).
+  --retries N — сколько раз повторять объект при временных ошибках.
+`
+}
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/human"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+// duEntry — итоги по одному префиксу; она же запись для --output
+type duEntry struct {
+	Prefix  string `json:"prefix"`
+	Size    int64  `json:"size"` // текущие версии
+	Count   int64  `json:"objects"`
+	OldSize int64  `json:"noncurrent_size"` // старые (noncurrent) версии, только с --all-versions
+	OldCnt  int64  `json:"noncurrent_versions"`
+	Markers int64  `json:"delete_markers"`  // маркеры удаления, только с --all-versions
+	Total   bool   `json:"total,omitempty"` // итог по всему префиксу запроса
+}
+
+func (e *duEntry) total() int64 { return e.Size + e.OldSize }
+
+// duTable — итоги по префиксам до заданной глубины; ключ — путь от исходного префикса
+type duTable map[string]*duEntry
+
+// add — учесть объект во всех его «папках» не глубже depth
+func (t duTable) add(rel string, v s3client.VersionInfo, depth int) {
+	segs := strings.Split(rel, "/")
+	dirs := segs[:len(segs)-1]
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	p := ""
+	for _, d := range dirs {
+		p += d + "/"
+		e := t[p]
+		if e == nil {
+			e = &duEntry{Prefix: p}
+			t[p] = e
+		}
+		e.count(v)
+	}
+}
+
+func (e *duEntry) count(v s3client.VersionInfo) {
+	switch {
+	case v.DeleteMarker:
+		e.Markers++
+	case v.IsLatest:
+		e.Size += v.Size
+		e.Count++
+	default:
+		e.OldSize += v.Size
+		e.OldCnt++
+	}
+}
+
+func (t duTable) merge(o duTable) {
+	for p, e := range o {
+		cur := t[p]
+		if cur == nil {
+			t[p] = e
+			continue
+		}
+		cur.Size += e.Size
+		cur.Count += e.Count
+		cur.OldSize += e.OldSize
+		cur.OldCnt += e.OldCnt
+		cur.Markers += e.Markers
+	}
+}
+
+func runDu(args []string, cfgPath string, verbose bool) (int, error) {
+	// du <alias>/<bucket>/<prefix?> [--depth N] [--all-versions] [-j N]
+	depth := 1
+	jobs := 8
+	allVersions := false
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-d", "--depth":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --depth требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				return 4, fmt.Errorf("некорректное значение для --depth: %q", args[i+1])
+			}
+			depth = n
+			i++
+		case "-j", "--jobs":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг -j требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return 4, fmt.Errorf("некорректное значение для -j: %q", args[i+1])
+			}
+			jobs = n
+			i++
+		case "--all-versions":
+			allVersions = true
+		case "-h", "--help":
+			fmt.Print(duUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'du': %q\n\n%s", args[i], duUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/prefix]\n\n%s", duUsage())
+	}
+
+	sp, err := parseS3Path(pos[0])
+	if err != nil {
+		return 4, err
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", sp.Alias)
+		}
+		return 1, err
+	}
+
+	// без общего таймаута: подсчёт большого бакета идёт дольше пары минут, прервать — Ctrl+C
+	ctx := rootCtx
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+
+	// "logs" — каталог logs/, а не все ключи на "logs" (logs2/, logs.tar)
+	prefix := sp.Key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	total := &duEntry{Prefix: prefix}
+	table, err := duCollect(ctx, client, sp.Bucket, prefix, depth, jobs, allVersions, total)
+	if err != nil {
+		return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
+	}
+
+	rows := make([]*duEntry, 0, len(table))
+	for _, e := range table {
+		rows = append(rows, e)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].total() != rows[j].total() {
+			return rows[i].total() > rows[j].total()
+		}
+		return rows[i].Prefix < rows[j].Prefix
+	})
+
+	if structured() {
+		rw := newRecordWriter("prefix", "size", "objects", "noncurrent_size", "noncurrent_versions", "delete_markers", "total")
+		for _, e := range rows {
+			rw.write(e)
+		}
+		total.Total = true
+		rw.write(total)
+		rw.close()
+		return 0, nil
+	}
+	if allVersions {
+		fmt.Println("      size   objects  noncurrent  versions   markers  prefix")
+	} else {
+		fmt.Println("      size   objects  prefix")
+	}
+	printRow := func(e *duEntry, name string) {
+		if allVersions {
+			fmt.Printf("%10s  %8d  %10s  %8d  %8d  %s\n", human.Bytes(e.Size), e.Count, human.Bytes(e.OldSize), e.OldCnt, e.Markers, name)
+			return
+		}
+		fmt.Printf("%10s  %8d  %s\n", human.Bytes(e.Size), e.Count, name)
+	}
+	for _, e := range rows {
+		printRow(e, e.Prefix)
+	}
+	printRow(total, fmt.Sprintf("итого s3://%s/%s", sp.Bucket, prefix))
+	return 0, nil
+}
+
+// duCollect — обойти префикс: первый уровень листингом с разделителем, каждая «папка»
+// первого уровня считается отдельным воркером. total получает итог по всему префиксу.
+func duCollect(ctx context.Context, client *s3client.Client, bucket, prefix string, depth, jobs int, allVersions bool, total *duEntry) (duTable, error) {
+	var (
+		folders []string
+		top     []s3client.VersionInfo
+	)
+	if allVersions {
+		f, vs, err := client.ListVersionsOneLevel(ctx, bucket, prefix)
+		if err != nil {
+			return nil, err
+		}
+		folders, top = f, vs
+	} else {
+		f, objs, err := client.ListOneLevel(ctx, bucket, prefix)
+		if err != nil {
+			return nil, err
+		}
+		folders = f
+		for _, o := range objs {
+			top = append(top, s3client.VersionInfo{Key: o.Key, Size: o.Size, IsLatest: true})
+		}
+	}
+	for _, v := range top {
+		total.count(v)
+	}
+
+	table := make(duTable)
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobsCh := make(chan string)
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for folder := range jobsCh {
+				local := make(duTable)
+				sub := &duEntry{}
+				fn := func(v s3client.VersionInfo) error {
+					sub.count(v)
+					local.add(strings.TrimPrefix(v.Key, prefix), v, depth)
+					return nil
+				}
+				var err error
+				if allVersions {
+					err = client.WalkVersions(ctx, bucket, folder, fn)
+				} else {
+					err = client.WalkObjects(ctx, bucket, folder, func(o s3client.ObjectInfo) error {
+						return fn(s3client.VersionInfo{Key: o.Key, Size: o.Size, IsLatest: true})
+					})
+				}
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+				} else {
+					table.merge(local)
+					total.Size += sub.Size
+					total.Count += sub.Count
+					total.OldSize += sub.OldSize
+					total.OldCnt += sub.OldCnt
+					total.Markers += sub.Markers
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, f := range folders {
+		select {
+		case jobsCh <- f:
+		case <-ctx.Done():
+		}
+	}
+	close(jobsCh)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return table, ctx.Err()
+}
+
+func duUsage() string {
+	return `Использование:
+  s3cli du <alias>/<bucket>/<prefix?> [--depth N] [--all-versions] [-j N]
+
+Описание:
+  Считает суммарный размер и число объектов по «папкам» под префиксом — чтобы понять,
+  что съедает квоту. Строки отсортированы по размеру, последняя — итог по префиксу.
+  --depth N (-d N) — до какой глубины показывать папки (по умолчанию 1; 0 — только итог).
+  --all-versions — учитывать и старые (noncurrent) версии и маркеры удаления, отдельными
+  колонками; сортировка — по сумме текущих и старых версий.
+  -j N — сколько папок первого уровня листать параллельно (по умолчанию 8).
+Пример:
+  s3cli du s3s7/fao_qa/ --depth 2
+`
+}
@@ -0,0 +1,359 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/human"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+// findQuery — условия find; все заданные должны выполняться одновременно
+type findQuery struct {
+	names   []string // шаблоны имени (последнего сегмента ключа), любой из них
+	newer   time.Time
+	older   time.Time
+	larger  int64 // -1 — не задано
+	smaller int64
+}
+
+func (q *findQuery) empty() bool {
+	return len(q.names) == 0 && q.newer.IsZero() && q.older.IsZero() && q.larger < 0 && q.smaller < 0
+}
+
+func (q *findQuery) match(o s3client.ObjectInfo) bool {
+	if len(q.names) > 0 {
+		base := path.Base(o.Key)
+		if strings.HasSuffix(o.Key, "/") {
+			base += "/"
+		}
+		ok := false
+		for _, n := range q.names {
+			if m, _ := path.Match(n, base); m {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if !q.newer.IsZero() && !o.LastModified.After(q.newer) {
+		return false
+	}
+	if !q.older.IsZero() && !o.LastModified.Before(q.older) {
+		return false
+	}
+	if q.larger >= 0 && o.Size <= q.larger {
+		return false
+	}
+	if q.smaller >= 0 && o.Size >= q.smaller {
+		return false
+	}
+	return true
+}
+
+// parseAge — момент времени для --newer/--older: возраст (90s, 15m, 12h, 7d, 2w)
+// отсчитывается от now, дата — 2025-01-02, 2025-01-02T15:04 или RFC 3339
+func parseAge(v string, now time.Time) (time.Time, error) {
+	units := map[byte]time.Duration{'s': time.Second, 'm': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if len(v) > 1 {
+		if u, ok := units[v[len(v)-1]]; ok {
+			if n, err := strconv.ParseFloat(v[:len(v)-1], 64); err == nil && n >= 0 {
+				return now.Add(-time.Duration(n * float64(u))), nil
+			}
+		}
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("некорректное время: %q (ожидаю возраст вроде 7d, 12h или дату 2025-01-02)", v)
+}
+
+// splitCommand — разбить строку --exec на аргументы как это сделал бы sh:
+// пробелы разделяют, '...' и "..." группируют, \ экранирует. Shell не запускаем,
+// поэтому ключ, подставленный вместо {}, никогда не интерпретируется как код.
+func splitCommand(s string) ([]string, error) {
+	var (
+		args  []string
+		cur   strings.Builder
+		inArg bool
+		quote rune
+	)
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(rs) && strings.ContainsRune(`"\$`+"`", rs[i+1]):
+				i++
+				cur.WriteRune(rs[i])
+			default:
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '\\' && i+1 < len(rs):
+			i++
+			cur.WriteRune(rs[i])
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("незакрытая кавычка в --exec: %s", s)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("пустая команда в --exec")
+	}
+	return args, nil
+}
+
+// findDeleteRecord — итог find --delete для --output
+type findDeleteRecord struct {
+	Bucket  string `json:"bucket"`
+	Prefix  string `json:"prefix"`
+	Found   int    `json:"found"`
+	Deleted int    `json:"deleted"`
+}
+
+func runFind(args []string, cfgPath string, verbose bool) (int, error) {
+	// find <alias>/<bucket>/<prefix?> [--name GLOB] [--newer T] [--older T] [--larger SIZE] [--smaller SIZE]
+	//      [--exec CMD | --delete | --print0]
+	now := time.Now()
+	q := findQuery{larger: -1, smaller: -1}
+	var (
+		execCmd []string
+		action  string
+		pos     []string
+	)
+	setAction := func(a string) error {
+		if action != "" && action != a {
+			return fmt.Errorf("флаги --exec, --delete и --print0 взаимоисключающие")
+		}
+		action = a
+		return nil
+	}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--name", "--newer", "--older", "--larger", "--smaller", "--exec":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг %s требует значение", args[i])
+			}
+			v := args[i+1]
+			var err error
+			switch args[i] {
+			case "--name":
+				if _, err = path.Match(v, ""); err != nil {
+					err = fmt.Errorf("некорректный шаблон для --name: %q", v)
+				}
+				q.names = append(q.names, v)
+			case "--newer":
+				q.newer, err = parseAge(v, now)
+			case "--older":
+				q.older, err = parseAge(v, now)
+			case "--larger":
+				q.larger, err = human.ParseBytes(v)
+			case "--smaller":
+				q.smaller, err = human.ParseBytes(v)
+			case "--exec":
+				if err = setAction("exec"); err == nil {
+					execCmd, err = splitCommand(v)
+				}
+			}
+			if err != nil {
+				return 4, err
+			}
+			i++
+		case "--delete":
+			if err := setAction("delete"); err != nil {
+				return 4, err
+			}
+		case "--print0":
+			if err := setAction("print0"); err != nil {
+				return 4, err
+			}
+		case "-h", "--help":
+			fmt.Print(findUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'find': %q\n\n%s", args[i], findUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/prefix]\n\n%s", findUsage())
+	}
+
+	sp, err := parseS3Path(pos[0])
+	if err != nil {
+		return 4, err
+	}
+	if action == "delete" && sp.Key == "" && q.empty() {
+		return 4, fmt.Errorf("find --delete без префикса и условий удалит весь бакет — так нельзя")
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", sp.Alias)
+		}
+		return 1, err
+	}
+
+	// без общего таймаута: обход большого бакета идёт дольше пары минут, прервать — Ctrl+C
+	ctx := rootCtx
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+
+	var (
+		found, deleted, failed int
+		batch                  []string
+		startErr               error
+	)
+	var rw *recordWriter
+	if action == "" && structured() {
+		rw = newRecordWriter(objectCols...)
+	}
+	flush := func() error {
+		n, err := client.DeleteKeys(ctx, sp.Bucket, batch)
+		deleted += n
+		batch = batch[:0]
+		return err
+	}
+
+	err = client.WalkObjects(ctx, sp.Bucket, sp.Key, func(o s3client.ObjectInfo) error {
+		if !q.match(o) {
+			return nil
+		}
+		found++
+		// тот же вид пути, что принимают остальные команды: alias/bucket/key
+		p := sp.Alias + "/" + sp.Bucket + "/" + o.Key
+		switch action {
+		case "print0":
+			fmt.Print(p + "\x00")
+		case "delete":
+			batch = append(batch, o.Key)
+			if len(batch) == 1000 {
+				return flush()
+			}
+		case "exec":
+			argv := make([]string, len(execCmd))
+			for i, a := range execCmd {
+				argv[i] = strings.ReplaceAll(a, "{}", p)
+			}
+			cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := cmd.Run(); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				var ee *exec.ExitError
+				if !errors.As(err, &ee) {
+					// команда не запустилась вовсе — дальше будет то же самое
+					startErr = fmt.Errorf("не удалось запустить %q: %w", argv[0], err)
+					return startErr
+				}
+				fmt.Fprintf(os.Stderr, "%s: команда завершилась с кодом %d\n", p, ee.ExitCode())
+				failed++
+			}
+		default:
+			if rw != nil {
+				rec := newObjectRecord(sp.Bucket, "", o)
+				rec.Name, rec.Path = "", p
+				rw.write(rec)
+				return nil
+			}
+			fmt.Println(p)
+		}
+		return nil
+	})
+	if rw != nil {
+		rw.finish(err)
+	}
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	if action == "delete" {
+		if structured() {
+			if err == nil {
+				emitRecord(findDeleteRecord{Bucket: sp.Bucket, Prefix: sp.Key, Found: found, Deleted: deleted}, "bucket", "prefix", "found", "deleted")
+			}
+		} else {
+			fmt.Printf("Найдено: %d, удалено объектов: %d\n", found, deleted)
+		}
+	}
+	if startErr != nil {
+		return 1, startErr
+	}
+	if err != nil {
+		return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
+	}
+	if failed > 0 {
+		return 1, fmt.Errorf("--exec завершился с ошибкой для %d из %d объектов", failed, found)
+	}
+	return 0, nil
+}
+
+func findUsage() string {
+	return `Использование:
+  s3cli find <alias>/<bucket>/<prefix?> [--name GLOB] [--newer T] [--older T]
+             [--larger SIZE] [--smaller SIZE] [--exec CMD | --delete | --print0]
+
+Описание:
+  Ищет объекты под префиксом и выводит их по мере листинга в виде alias/bucket/key —
+  так, как их принимают остальные команды. Все заданные условия должны выполняться.
+  --name GLOB — имя объекта (часть ключа после последнего "/"), например '*.log';
+  флаг повторяемый, подходит любой из шаблонов.
+  --newer T / --older T — изменён позже / раньше T. T — возраст (90s, 15m, 12h, 7d, 2w)
+  или дата (2025-01-02, 2025-01-02T15:04, RFC 3339).
+  --larger SIZE / --smaller SIZE — больше / меньше SIZE (100M, 1.5GiB, 512K).
+  --exec CMD — выполнить CMD для каждого найденного, {} заменяется путём объекта.
+  CMD разбирается на аргументы по правилам shell (кавычки, \), но запускается без
+  shell — ключи с пробелами и спецсимволами подставляются как есть. Ненулевой код
+  возврата команды — ошибка этого объекта, поиск продолжается.
+  --delete — удалить найденное (пакетами по 1000).
+  --print0 — разделять пути символом NUL, для xargs -0.
+Пример:
+  s3cli find s3s7/logs/ --name '*.log' --older 30d --larger 100M --delete
+  s3cli find s3s7/logs/ --newer 7d --exec 's3cli get {} ./recent/'
+  s3cli find s3s7/logs/ --name '*.gz' --print0 | xargs -0 -n1 s3cli stat
+`
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+	"gopkg.in/yaml.v3"
+)
+
+// lifecycleFile — YAML-файл правил: тот же формат читает set и печатает get
+type lifecycleFile struct {
+	Rules []s3client.LifecycleRule `yaml:"rules"`
+}
+
+var lifecycleCols = []string{"id", "disabled", "prefix", "tags", "larger_than", "smaller_than", "expire", "transitions", "noncurrent", "abort_incomplete_days"}
+
+// lifecycleResult — итог lifecycle set/rm для --output
+type lifecycleResult struct {
+	Bucket string `json:"bucket"`
+	Status string `json:"status"` // set | removed
+	Rules  int    `json:"rules"`
+}
+
+// readLifecycleFile — правила из файла ("-" — stdin). Незнакомые поля — ошибка:
+// опечатка в имени поля иначе молча выключила бы часть правила.
+func readLifecycleFile(path string) ([]s3client.LifecycleRule, error) {
+	var (
+		b   []byte
+		err error
+	)
+	if path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать %q: %w", path, err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	var f lifecycleFile
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("некорректный файл правил %q: %w", path, err)
+	}
+	return f.Rules, nil
+}
+
+func runLifecycle(args []string, cfgPath string, verbose bool) (int, error) {
+	// lifecycle get|set|rm <alias>/<bucket> [FILE]
+	if len(args) == 0 {
+		return 4, fmt.Errorf("нужно указать подкоманду: get, set или rm\n\n%s", lifecycleUsage())
+	}
+	action := args[0]
+	switch action {
+	case "get", "set", "rm":
+	case "-h", "--help", "help":
+		fmt.Print(lifecycleUsage())
+		return 0, nil
+	default:
+		return 4, fmt.Errorf("неизвестная подкоманда lifecycle: %q\n\n%s", action, lifecycleUsage())
+	}
+	// set принимает ещё и файл
+	maxPos := 1
+	if action == "set" {
+		maxPos = 2
+	}
+	var pos []string
+	for _, a := range args[1:] {
+		switch a {
+		case "-h", "--help":
+			fmt.Print(lifecycleUsage())
+			return 0, nil
+		default:
+			if (strings.HasPrefix(a, "-") && a != "-") || len(pos) >= maxPos {
+				return 4, fmt.Errorf("лишний аргумент для 'lifecycle %s': %q\n\n%s", action, a, lifecycleUsage())
+			}
+			pos = append(pos, a)
+		}
+	}
+	if len(pos) < maxPos {
+		if action == "set" && len(pos) == 1 {
+			return 4, fmt.Errorf("нужно указать файл правил (или - для stdin)\n\n%s", lifecycleUsage())
+		}
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket\n\n%s", lifecycleUsage())
+	}
+
+	sp, err := parseBucketPath(pos[0], "lifecycle "+action)
+	if err != nil {
+		return 4, err
+	}
+	var rules []s3client.LifecycleRule
+	if action == "set" {
+		// файл читаем до похода в S3: с ошибкой в YAML запрос не нужен
+		if rules, err = readLifecycleFile(pos[1]); err != nil {
+			return 4, err
+		}
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", sp.Alias)
+		}
+		return 1, err
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 2*time.Minute)
+	defer cancel()
+
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+	notFound := fmt.Sprintf("Бакет не найден: %s", sp.Bucket)
+
+	switch action {
+	case "get":
+		rules, err := client.GetLifecycle(ctx, sp.Bucket)
+		if err != nil {
+			return handleAWSError(err, verbose, notFound, "Доступ запрещён")
+		}
+		if structured() {
+			rw := newRecordWriter(lifecycleCols...)
+			for _, r := range rules {
+				rw.write(r)
+			}
+			rw.close()
+			return 0, nil
+		}
+		if rules == nil {
+			// пустой список, а не null: такой файл set примет и правила удалит
+			rules = []s3client.LifecycleRule{}
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(lifecycleFile{Rules: rules}); err != nil {
+			return 1, err
+		}
+		_ = enc.Close()
+		fmt.Print(buf.String())
+		return 0, nil
+
+	case "set":
+		if err := client.SetLifecycle(ctx, sp.Bucket, rules); err != nil {
+			if errors.Is(err, s3client.ErrInvalidRule) {
+				return 4, err
+			}
+			// текст отказа S3 здесь полезнее общего «неверные аргументы»: он называет поле
+			var (
+				re *smithyhttp.ResponseError
+				ae smithy.APIError
+			)
+			if errors.As(err, &re) && re.HTTPStatusCode() == 400 && errors.As(err, &ae) && ae.ErrorMessage() != "" {
+				return 4, withStatus(fmt.Errorf("S3 отклонил правила: %s", ae.ErrorMessage()), err)
+			}
+			return handleAWSError(err, verbose, notFound, "Доступ запрещён")
+		}
+		if structured() {
+			status := "set"
+			if len(rules) == 0 {
+				status = "removed"
+			}
+			emitRecord(lifecycleResult{Bucket: sp.Bucket, Status: status, Rules: len(rules)}, "bucket", "status", "rules")
+			return 0, nil
+		}
+		if len(rules) == 0 {
+			fmt.Printf("Правил в файле нет — правила жизненного цикла бакета %q удалены.\n", sp.Bucket)
+			return 0, nil
+		}
+		fmt.Printf("Правила жизненного цикла бакета %q заменены: %d\n", sp.Bucket, len(rules))
+		return 0, nil
+
+	default: // rm
+		if err := client.DeleteLifecycle(ctx, sp.Bucket); err != nil {
+			return handleAWSError(err, verbose, notFound, "Доступ запрещён")
+		}
+		if structured() {
+			emitRecord(lifecycleResult{Bucket: sp.Bucket, Status: "removed"}, "bucket", "status", "rules")
+			return 0, nil
+		}
+		fmt.Printf("Правила жизненного цикла бакета %q удалены.\n", sp.Bucket)
+		return 0, nil
+	}
+}
+
+func lifecycleUsage() string {
+	return `Использование:
+  s3cli lifecycle get <alias>/<bucket> > rules.yaml
+  s3cli lifecycle set <alias>/<bucket> rules.yaml|-
+  s3cli lifecycle rm <alias>/<bucket>
+
+Описание:
+  Правила жизненного цикла бакета: когда удалять объекты и старые версии,
+  когда переносить в другой класс хранения, когда прерывать брошенные загрузки.
+  get — текущие правила в YAML; его же принимает set, так что правила можно
+  хранить в git и править там.
+  set — заменить все правила бакета правилами из файла ("-" — из stdin).
+  Пустой список rules — то же, что rm.
+  rm — удалить все правила.
+Формат файла (все поля, кроме действия, необязательные):
+  rules:
+    - id: logs
+      disabled: true           # правило есть, но не действует
+      prefix: logs/            # фильтр: префикс ключа
+      tags: {env: tmp}         #   теги объекта
+      larger_than: 1048576     #   размер, байт (и smaller_than)
+      expire:
+        days: 30               # удалить через 30 дней; вместо days — date: 2025-01-02
+                               # или delete_markers: true (снимать маркеры без версий)
+      transitions:
+        - days: 7
+          storage_class: STANDARD_IA
+      noncurrent:              # старые версии
+        expire_days: 90
+        keep: 3                # сколько новых старых версий не трогать
+        transitions:
+          - days: 30
+            storage_class: GLACIER
+      abort_incomplete_days: 7 # прерывать незавершённые загрузки (см. multipart)
+Пример:
+  s3cli lifecycle get s3s7/logs > logs-lifecycle.yaml
+  s3cli lifecycle set s3s7/logs logs-lifecycle.yaml
+`
+}
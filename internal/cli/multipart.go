@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wolfsTail/s3cli/internal/human"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+// uploadRecord — незавершённая загрузка в multipart ls (и план multipart abort --dry-run)
+type uploadRecord struct {
+	Bucket    string     `json:"bucket"`
+	Key       string     `json:"key"`
+	UploadID  string     `json:"upload_id"`
+	Initiated *time.Time `json:"initiated,omitempty"`
+	Parts     int        `json:"parts"`
+	Size      int64      `json:"size"`
+}
+
+var uploadCols = []string{"bucket", "key", "upload_id", "initiated", "parts", "size"}
+
+// abortRecord — итог multipart abort для --output
+type abortRecord struct {
+	Bucket  string `json:"bucket"`
+	Aborted int    `json:"aborted"`
+	Failed  int    `json:"failed"`
+	Freed   int64  `json:"freed"` // байт в частях прерванных загрузок
+}
+
+// uploadState — загрузка вместе с тем, что уже лежит на сервере
+type uploadState struct {
+	s3client.UploadInfo
+	parts int
+	bytes int64
+}
+
+func newUploadRecord(bucket string, u uploadState) uploadRecord {
+	rec := uploadRecord{Bucket: bucket, Key: u.Key, UploadID: u.UploadID, Parts: u.parts, Size: u.bytes}
+	if !u.Initiated.IsZero() {
+		t := u.Initiated.UTC()
+		rec.Initiated = &t
+	}
+	return rec
+}
+
+// collectUploads — загрузки под префиксом, начатые раньше before (нулевое — все),
+// с числом и объёмом уже загруженных частей
+func collectUploads(ctx context.Context, client *s3client.Client, bucket, prefix string, before time.Time) ([]uploadState, error) {
+	uploads, err := client.ListUploads(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	var res []uploadState
+	for _, u := range uploads {
+		if !before.IsZero() && !u.Initiated.Before(before) {
+			continue
+		}
+		parts, bytes, err := client.UploadParts(ctx, bucket, u.Key, u.UploadID)
+		if err != nil {
+			if bucketErrorCode(err) == "NoSuchUpload" {
+				// загрузку успели завершить или прервать, пока мы листали
+				continue
+			}
+			return nil, err
+		}
+		res = append(res, uploadState{UploadInfo: u, parts: parts, bytes: bytes})
+	}
+	return res, nil
+}
+
+func runMultipart(args []string, cfgPath string, verbose bool) (int, error) {
+	// multipart ls|abort ...
+	if len(args) == 0 {
+		return 4, fmt.Errorf("нужно указать подкоманду: ls или abort\n\n%s", multipartUsage())
+	}
+	switch args[0] {
+	case "ls":
+		return runMultipartLs(args[1:], cfgPath, verbose)
+	case "abort":
+		return runMultipartAbort(args[1:], cfgPath, verbose)
+	case "-h", "--help", "help":
+		fmt.Print(multipartUsage())
+		return 0, nil
+	default:
+		return 4, fmt.Errorf("неизвестная подкоманда multipart: %q\n\n%s", args[0], multipartUsage())
+	}
+}
+
+func runMultipartLs(args []string, cfgPath string, verbose bool) (int, error) {
+	// multipart ls <alias>/<bucket>/<prefix?> [--older-than T]
+	var (
+		before time.Time
+		pos    []string
+	)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--older-than":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --older-than требует время")
+			}
+			t, err := parseAge(args[i+1], time.Now())
+			if err != nil {
+				return 4, err
+			}
+			before = t
+			i++
+		case "-h", "--help":
+			fmt.Print(multipartUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'multipart ls': %q\n\n%s", args[i], multipartUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/prefix]\n\n%s", multipartUsage())
+	}
+	sp, err := parseS3Path(pos[0])
+	if err != nil {
+		return 4, err
+	}
+
+	// без общего таймаута: по каждой загрузке отдельно листаются части, прервать — Ctrl+C
+	ctx := rootCtx
+	client, code, err := clientFor(ctx, cfgPath, sp)
+	if err != nil {
+		return code, err
+	}
+	uploads, err := collectUploads(ctx, client, sp.Bucket, sp.Key, before)
+	if err != nil {
+		return handleAWSError(err, verbose, fmt.Sprintf("Бакет не найден: %s", sp.Bucket), "Доступ запрещён")
+	}
+
+	if structured() {
+		rw := newRecordWriter(uploadCols...)
+		for _, u := range uploads {
+			rw.write(newUploadRecord(sp.Bucket, u))
+		}
+		rw.close()
+		return 0, nil
+	}
+	if len(uploads) == 0 {
+		fmt.Println("Незавершённых загрузок нет")
+		return 0, nil
+	}
+	printUploads(uploads)
+	return 0, nil
+}
+
+// printUploads — таблица загрузок и итог по ним
+func printUploads(uploads []uploadState) {
+	fmt.Printf("%-16s  %6s  %10s  %-36s  %s\n", "started", "parts", "size", "upload id", "key")
+	var total int64
+	for _, u := range uploads {
+		total += u.bytes
+		fmt.Printf("%-16s  %6d  %10s  %-36s  %s\n", human.Time(u.Initiated), u.parts, human.Bytes(u.bytes), u.UploadID, u.Key)
+	}
+	fmt.Printf("Итого: %s, в частях %s\n",
+		human.Count(int64(len(uploads)), "загрузка", "загрузки", "загрузок"), human.Bytes(total))
+}
+
+func runMultipartAbort(args []string, cfgPath string, verbose bool) (int, error) {
+	// multipart abort <alias>/<bucket>/<key> --upload-id ID
+	// multipart abort <alias>/<bucket>/<prefix?> --older-than T | --all [--dry-run]
+	var (
+		uploadID string
+		before   time.Time
+		all      bool
+		dryRun   bool
+		pos      []string
+	)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--upload-id":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --upload-id требует значение")
+			}
+			uploadID = args[i+1]
+			i++
+		case "--older-than":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --older-than требует время")
+			}
+			t, err := parseAge(args[i+1], time.Now())
+			if err != nil {
+				return 4, err
+			}
+			before = t
+			i++
+		case "--all":
+			all = true
+		case "--dry-run":
+			dryRun = true
+		case "-h", "--help":
+			fmt.Print(multipartUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'multipart abort': %q\n\n%s", args[i], multipartUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/key]\n\n%s", multipartUsage())
+	}
+	bulk := all || !before.IsZero()
+	if uploadID != "" && bulk {
+		return 4, fmt.Errorf("--upload-id прерывает одну загрузку, --older-than и --all — несколько; выберите что-то одно")
+	}
+	if uploadID == "" && !bulk {
+		return 4, fmt.Errorf("укажите, что прервать: --upload-id ID, --older-than T или --all\n\n%s", multipartUsage())
+	}
+	if uploadID != "" && dryRun {
+		return 4, fmt.Errorf("--dry-run работает только с --older-than и --all")
+	}
+	sp, err := parseS3Path(pos[0])
+	if err != nil {
+		return 4, err
+	}
+	if uploadID != "" && (sp.Key == "" || strings.HasSuffix(sp.Key, "/")) {
+		return 4, fmt.Errorf("для --upload-id нужен ключ объекта: alias/bucket/key")
+	}
+
+	if uploadID != "" {
+		ctx, cancel := context.WithTimeout(rootCtx, 2*time.Minute)
+		defer cancel()
+		client, code, err := clientFor(ctx, cfgPath, sp)
+		if err != nil {
+			return code, err
+		}
+		if err := client.AbortUpload(ctx, sp.Bucket, sp.Key, uploadID); err != nil {
+			return handleAWSError(err, verbose, fmt.Sprintf("Загрузка не найдена: %s (%s)", sp.Key, uploadID), "Доступ запрещён")
+		}
+		if structured() {
+			emitRecord(abortRecord{Bucket: sp.Bucket, Aborted: 1}, "bucket", "aborted", "failed", "freed")
+			return 0, nil
+		}
+		fmt.Printf("Загрузка %s прервана: %s\n", uploadID, sp.Key)
+		return 0, nil
+	}
+
+	// без общего таймаута: загрузок может быть много, прервать — Ctrl+C
+	ctx := rootCtx
+	client, code, err := clientFor(ctx, cfgPath, sp)
+	if err != nil {
+		return code, err
+	}
+	uploads, err := collectUploads(ctx, client, sp.Bucket, sp.Key, before)
+	if err != nil {
+		return handleAWSError(err, verbose, fmt.Sprintf("Бакет не найден: %s", sp.Bucket), "Доступ запрещён")
+	}
+
+	if dryRun {
+		if structured() {
+			rw := newRecordWriter(uploadCols...)
+			for _, u := range uploads {
+				rw.write(newUploadRecord(sp.Bucket, u))
+			}
+			rw.close()
+			return 0, nil
+		}
+		if len(uploads) == 0 {
+			fmt.Println("Прерывать нечего")
+			return 0, nil
+		}
+		printUploads(uploads)
+		return 0, nil
+	}
+
+	rec := abortRecord{Bucket: sp.Bucket}
+	for _, u := range uploads {
+		if err := client.AbortUpload(ctx, sp.Bucket, u.Key, u.UploadID); err != nil {
+			if ctx.Err() != nil {
+				return 1, ctx.Err()
+			}
+			if bucketErrorCode(err) == "NoSuchUpload" {
+				// успели завершить или прервать без нас — частей уже нет
+				continue
+			}
+			rec.Failed++
+			if !structured() {
+				fmt.Fprintf(os.Stderr, "%s (%s): %v\n", u.Key, u.UploadID, err)
+			}
+			continue
+		}
+		rec.Aborted++
+		rec.Freed += u.bytes
+	}
+
+	if structured() {
+		emitRecord(rec, "bucket", "aborted", "failed", "freed")
+	} else {
+		fmt.Printf("Прервано загрузок: %d, освобождено %s\n", rec.Aborted, human.Bytes(rec.Freed))
+	}
+	if rec.Failed > 0 {
+		if structured() {
+			return 1, nil
+		}
+		return 1, fmt.Errorf("не удалось прервать %d из %d загрузок", rec.Failed, len(uploads))
+	}
+	return 0, nil
+}
+
+func multipartUsage() string {
+	return `Использование:
+  s3cli multipart ls <alias>/<bucket>/<prefix?> [--older-than T]
+  s3cli multipart abort <alias>/<bucket>/<key> --upload-id ID
+  s3cli multipart abort <alias>/<bucket>/<prefix?> --older-than T | --all [--dry-run]
+
+Описание:
+  Незавершённые multipart-загрузки: их оставляют прерванные put. Части таких
+  загрузок хранятся и оплачиваются, хотя объекта нет и в ls его не видно.
+  ls — загрузки под префиксом: когда начата, сколько частей и байт уже загружено,
+  upload id и ключ.
+  abort — прервать загрузку: S3 удаляет её части.
+  --upload-id ID — одну загрузку ключа (ID из multipart ls).
+  --older-than T — все под префиксом, начатые раньше T: возраст (12h, 7d, 2w) или дата.
+  --all — все под префиксом.
+  --dry-run — только показать, что будет прервано.
+  Прерванный put, запущенный снова, начнёт загрузку этого файла заново.
+Пример:
+  s3cli multipart ls s3s7/backups/
+  s3cli multipart abort s3s7/backups/ --older-than 7d
+  s3cli multipart abort s3s7/backups/db.tar --upload-id 2~abc...
+`
+}
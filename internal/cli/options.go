@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/filter"
+	"github.com/wolfsTail/s3cli/internal/human"
+	"github.com/wolfsTail/s3cli/internal/transfer"
+)
+
+// retryPolicy — флаг --retries важнее настройки алиаса; -1 означает «флаг не задан»
+func retryPolicy(flag int, a config.Alias) transfer.RetryPolicy {
+	n := transfer.DefaultRetries
+	switch {
+	case flag >= 0:
+		n = flag
+	case a.Retries > 0:
+		n = a.Retries
+	}
+	return transfer.NewRetryPolicy(n)
+}
+
+// parseRate — скорость вида 20MiB/s, 512K (суффикс /s необязателен)
+func parseRate(s string) (int64, error) {
+	n, err := human.ParseBytes(strings.TrimSuffix(strings.TrimSpace(s), "/s"))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("некорректная скорость: %q (пример: 20MiB/s)", s)
+	}
+	return n, nil
+}
+
+// rateLimiter — глобальный --limit-rate важнее limit_rate алиаса; 0 означает «флаг не задан»
+func rateLimiter(flag int64, a config.Alias) (*transfer.Limiter, error) {
+	if flag > 0 {
+		return transfer.NewLimiter(flag), nil
+	}
+	if a.LimitRate == "" {
+		return nil, nil
+	}
+	n, err := parseRate(a.LimitRate)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка в limit_rate алиаса: %w", err)
+	}
+	return transfer.NewLimiter(n), nil
+}
+
+// overwriteFlags — флаги политики перезаписи для put и get
+var overwriteFlags = map[string]transfer.Overwrite{
+	"--no-clobber":              transfer.OverwriteNever,
+	"--update":                  transfer.OverwriteNewer,
+	"--skip-existing-same-size": transfer.OverwriteSizeDiffers,
+}
+
+// setOverwrite — запомнить политику из флага; разные политики вместе не сочетаются
+func setOverwrite(cur *transfer.Overwrite, flag string) error {
+	ow := overwriteFlags[flag]
+	if *cur != transfer.OverwriteAlways && *cur != ow {
+		return fmt.Errorf("флаги --no-clobber, --update и --skip-existing-same-size взаимоисключающие")
+	}
+	*cur = ow
+	return nil
+}
+
+// addFilter — правила --include/--exclude добавляются в порядке флагов
+func addFilter(flt *filter.Filter, flag, pattern string) error {
+	if flag == "--include" {
+		return flt.Include(pattern)
+	}
+	return flt.Exclude(pattern)
+}
+
+// setLinks — режим символических ссылок из --links VALUE / --follow-symlinks;
+// allowed — какие значения допустимы в этой команде
+func setLinks(cur *transfer.LinkMode, v string, allowed ...string) error {
+	modes := map[string]transfer.LinkMode{"follow": transfer.LinksFollow, "store": transfer.LinksStore}
+	m, ok := modes[v]
+	if !ok || !slices.Contains(allowed, v) {
+		return fmt.Errorf("некорректное значение для --links: %q (ожидаю %s)", v, strings.Join(allowed, " или "))
+	}
+	if *cur != transfer.LinksDefault && *cur != m {
+		return fmt.Errorf("--follow-symlinks и --links=store взаимоисключающие")
+	}
+	*cur = m
+	return nil
+}
@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+// outputFormat — формат вывода команд (глобальный флаг --output / --json)
+type outputFormat int
+
+const (
+	outputTable outputFormat = iota // человекочитаемый вывод, как раньше
+	outputJSON                      // один JSON-документ: объект или массив записей
+	outputJSONL                     // по записи JSON на строку
+	outputCSV                       // заголовок и строки CSV
+)
+
+// output — выбранный формат; задаётся один раз в Run, как rootCtx
+var output = outputTable
+
+func parseOutput(v string) (outputFormat, error) {
+	switch v {
+	case "table":
+		return outputTable, nil
+	case "json":
+		return outputJSON, nil
+	case "jsonl":
+		return outputJSONL, nil
+	case "csv":
+		return outputCSV, nil
+	}
+	return 0, fmt.Errorf("некорректное значение для --output: %q (ожидаю json, jsonl, table или csv)", v)
+}
+
+// structured — включён ли машинно-читаемый вывод
+func structured() bool {
+	return output != outputTable
+}
+
+// recordWriter — пишет поток записей в выбранном формате по мере поступления.
+// Записи — структуры с json-тегами; имена полей и есть стабильный формат для скриптов,
+// для CSV cols задаёт набор и порядок колонок.
+type recordWriter struct {
+	w    io.Writer
+	cols []string
+	csv  *csv.Writer
+	n    int
+}
+
+func newRecordWriter(cols ...string) *recordWriter {
+	return &recordWriter{w: os.Stdout, cols: cols}
+}
+
+// header — заголовок CSV пишется перед первой строкой (или при close)
+func (rw *recordWriter) header() {
+	if output == outputCSV && rw.csv == nil {
+		rw.csv = csv.NewWriter(rw.w)
+		_ = rw.csv.Write(rw.cols)
+	}
+}
+
+func (rw *recordWriter) write(rec any) {
+	rw.header()
+	switch output {
+	case outputJSON:
+		b, _ := json.Marshal(rec)
+		if rw.n == 0 {
+			fmt.Fprintf(rw.w, "[\n  %s", b)
+		} else {
+			fmt.Fprintf(rw.w, ",\n  %s", b)
+		}
+	case outputJSONL:
+		b, _ := json.Marshal(rec)
+		fmt.Fprintf(rw.w, "%s\n", b)
+	case outputCSV:
+		_ = rw.csv.Write(csvRow(rec, rw.cols))
+		// строки нужны сразу, а не по заполнении буфера
+		rw.csv.Flush()
+	}
+	rw.n++
+}
+
+// close — дописать конец документа (для json — закрыть массив)
+func (rw *recordWriter) close() {
+	rw.header()
+	if output == outputJSON {
+		if rw.n == 0 {
+			fmt.Fprintln(rw.w, "[]")
+		} else {
+			fmt.Fprintln(rw.w, "\n]")
+		}
+	}
+	if rw.csv != nil {
+		rw.csv.Flush()
+	}
+}
+
+// openArray — массив json оборвавшегося листинга ещё не закрыт: запись об ошибке
+// станет его последним элементом, чтобы в stdout остался один документ
+var openArray bool
+
+// finish — close после потокового листинга: если он оборвался, не успев ничего вывести,
+// не пишем и пустой документ — останется только запись об ошибке; если успел —
+// массив json закроет writeError
+func (rw *recordWriter) finish(err error) {
+	switch {
+	case err == nil:
+		rw.close()
+	case rw.n == 0:
+	case output == outputJSON:
+		openArray = true
+	default:
+		rw.close()
+	}
+}
+
+// emitRecord — одна запись-результат команды; для json это объект, а не массив
+func emitRecord(rec any, cols ...string) {
+	switch output {
+	case outputJSON:
+		b, _ := json.MarshalIndent(rec, "", "  ")
+		fmt.Printf("%s\n", b)
+	default:
+		rw := newRecordWriter(cols...)
+		rw.write(rec)
+		rw.close()
+	}
+}
+
+// csvRow — значения полей записи в порядке cols, через её JSON-представление
+func csvRow(rec any, cols []string) []string {
+	b, _ := json.Marshal(rec)
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var m map[string]any
+	_ = dec.Decode(&m)
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		switch v := m[c].(type) {
+		case nil:
+		case string:
+			row[i] = v
+		case json.Number:
+			row[i] = v.String()
+		case bool:
+			row[i] = fmt.Sprint(v)
+		default:
+			// вложенные списки и объекты — как JSON в одной ячейке
+			vb, _ := json.Marshal(v)
+			row[i] = string(vb)
+		}
+	}
+	return row
+}
+
+// statusError — ошибка с HTTP-статусом и кодом ответа S3 (для записи об ошибке);
+// текст — как у handleAWSError, а статус и код сохраняются из исходной ошибки
+type statusError struct {
+	status int
+	code   string
+	err    error
+}
+
+// withStatus — приложить к сообщению статус и код S3 из исходной ошибки cause
+func withStatus(msg, cause error) error {
+	se := &statusError{err: msg}
+	var re *smithyhttp.ResponseError
+	if errors.As(cause, &re) {
+		se.status = re.HTTPStatusCode()
+	}
+	var ae smithy.APIError
+	if errors.As(cause, &ae) {
+		se.code = ae.ErrorCode()
+	}
+	return se
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+func (e *statusError) Unwrap() error { return e.err }
+
+// errorRecord — запись об ошибке команды в json/jsonl
+type errorRecord struct {
+	Error struct {
+		Message    string `json:"message"`
+		ExitCode   int    `json:"exit_code"`
+		HTTPStatus int    `json:"http_status,omitempty"`
+		Code       string `json:"code,omitempty"`
+	} `json:"error"`
+}
+
+// writeError — ошибку команды в json/jsonl печатаем записью в stdout, рядом с остальными;
+// для csv и таблицы она, как и раньше, уходит текстом в stderr
+func writeError(code int, err error) bool {
+	if output != outputJSON && output != outputJSONL {
+		return false
+	}
+	var rec errorRecord
+	rec.Error.Message = strings.TrimSpace(err.Error())
+	rec.Error.ExitCode = code
+	var se *statusError
+	if errors.As(err, &se) {
+		rec.Error.HTTPStatus, rec.Error.Code = se.status, se.code
+	} else {
+		var re *smithyhttp.ResponseError
+		if errors.As(err, &re) {
+			rec.Error.HTTPStatus = re.HTTPStatusCode()
+		}
+		var ae smithy.APIError
+		if errors.As(err, &ae) {
+			rec.Error.Code = ae.ErrorCode()
+		}
+	}
+	b, _ := json.Marshal(rec)
+	if openArray {
+		openArray = false
+		fmt.Printf(",\n  %s\n]\n", b)
+		return true
+	}
+	fmt.Printf("%s\n", b)
+	return true
+}
+
+// closeArray — дописать конец массива, если листинг оборвался, а команда всё же
+// завершилась без ошибки
+func closeArray() {
+	if openArray {
+		openArray = false
+		fmt.Println("\n]")
+	}
+}
+
+// objectRecord — объект или «папка» (type=prefix) в листингах ls, find, tree
+type objectRecord struct {
+	Type         string     `json:"type"`
+	Bucket       string     `json:"bucket"`
+	Key          string     `json:"key"`
+	Name         string     `json:"name,omitempty"` // путь от префикса запроса
+	Path         string     `json:"path,omitempty"` // alias/bucket/key, как его принимают команды
+	Size         int64      `json:"size"`
+	Objects      int64      `json:"objects,omitempty"` // у «папок» tree — сколько объектов внутри
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	ETag         string     `json:"etag,omitempty"`
+}
+
+var objectCols = []string{"type", "bucket", "key", "name", "path", "size", "objects", "last_modified", "etag"}
+
+func newObjectRecord(bucket, prefix string, o s3client.ObjectInfo) objectRecord {
+	rec := objectRecord{
+		Type:   "object",
+		Bucket: bucket,
+		Key:    o.Key,
+		Name:   strings.TrimPrefix(o.Key, prefix),
+		Size:   o.Size,
+		ETag:   o.ETag,
+	}
+	if !o.LastModified.IsZero() {
+		t := o.LastModified.UTC()
+		rec.LastModified = &t
+	}
+	return rec
+}
+
+// transferRecord — итог put/get/cp/mv: одиночной передачи или целого дерева
+type transferRecord struct {
+	Command     string       `json:"command"`
+	Status      string       `json:"status"` // ok | skipped | partial
+	Bucket      string       `json:"bucket"`
+	Key         string       `json:"key,omitempty"`
+	Local       string       `json:"local,omitempty"`
+	Dest        string       `json:"dest,omitempty"` // cp/mv: куда
+	Total       int          `json:"total"`
+	Transferred int          `json:"transferred"`
+	Dirs        int          `json:"dirs,omitempty"` // get: каталоги, созданные по маркерам "folder/"
+	Skipped     int          `json:"skipped"`
+	Failed      int          `json:"failed"`
+	FailedItems []reportItem `json:"failed_items,omitempty"`
+	Rejected    []reportItem `json:"rejected,omitempty"`
+	Warnings    []string     `json:"warnings,omitempty"`
+}
+
+var transferCols = []string{"command", "status", "bucket", "key", "local", "dest", "total", "transferred", "dirs", "skipped", "failed", "failed_items", "rejected", "warnings"}
+
+// status — ok, если ничего не потеряно, иначе partial
+func (r *transferRecord) status() string {
+	if r.Failed > 0 || len(r.Rejected) > 0 {
+		return "partial"
+	}
+	return "ok"
+}
+
+// printDone — итог одиночной операции: строкой msg в таблице, записью в json/csv
+func printDone(msg string, rec transferRecord) {
+	if structured() {
+		emitRecord(rec, transferCols...)
+		return
+	}
+	fmt.Println(msg)
+}
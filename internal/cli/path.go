@@ -1,8 +1,14 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
+
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/s3client"
 )
 
 type s3Path struct {
@@ -29,3 +35,39 @@ func parseS3Path(raw string) (s3Path, error) {
 	}
 	return sp, nil
 }
+
+// clientFor — клиент для алиаса из пути; код — как у команд: 2, если алиаса нет
+func clientFor(ctx context.Context, cfgPath string, sp s3Path) (*s3client.Client, int, error) {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return nil, 1, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return nil, 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", sp.Alias)
+		}
+		return nil, 1, err
+	}
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return nil, 1, err
+	}
+	return client, 0, nil
+}
+
+// isRemotePath — похож ли аргумент на alias/bucket[/key], а не на локальный путь
+func isRemotePath(raw string, cfg *config.Config) bool {
+	if strings.HasPrefix(raw, "s3://") {
+		return true
+	}
+	if _, err := os.Stat(raw); err == nil {
+		return false
+	}
+	first, _, ok := strings.Cut(raw, "/")
+	if !ok {
+		return false
+	}
+	_, err := cfg.GetAlias(first)
+	return err == nil
+}
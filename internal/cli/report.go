@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/wolfsTail/s3cli/internal/transfer"
+)
+
+// transferReport — отчёт о не переданных файлах (--report), его же читает --retry-from
+type transferReport struct {
+	Command string       `json:"command"`
+	Alias   string       `json:"alias"`
+	Bucket  string       `json:"bucket"`
+	Total   int          `json:"total"`
+	Failed  []reportItem `json:"failed"`
+	// Rejected — ключи, отклонённые как небезопасные; --retry-from их не повторяет
+	Rejected []reportItem `json:"rejected,omitempty"`
+}
+
+type reportItem struct {
+	Local string `json:"local"`
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+func newReport(cmd string, sp s3Path, total int, failed []transfer.FailedItem) transferReport {
+	rep := transferReport{
+		Command: cmd,
+		Alias:   sp.Alias,
+		Bucket:  sp.Bucket,
+		Total:   total,
+		Failed:  reportItems(failed),
+	}
+	return rep
+}
+
+func reportItems(failed []transfer.FailedItem) []reportItem {
+	out := make([]reportItem, 0, len(failed))
+	for _, f := range failed {
+		// абсолютный путь — чтобы --retry-from работал из любого каталога
+		local := f.Local
+		if local != "" {
+			if abs, err := filepath.Abs(local); err == nil {
+				local = abs
+			}
+		}
+		out = append(out, reportItem{Local: local, Key: f.Key, Error: f.Err.Error()})
+	}
+	return out
+}
+
+func writeReport(path string, rep transferReport) error {
+	b, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации отчёта: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("не удалось записать отчёт %q: %w", path, err)
+	}
+	return nil
+}
+
+func readReport(path, cmd string) (transferReport, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return transferReport{}, fmt.Errorf("не удалось прочитать отчёт %q: %w", path, err)
+	}
+	var rep transferReport
+	if err := json.Unmarshal(b, &rep); err != nil {
+		return transferReport{}, fmt.Errorf("повреждён отчёт %q: %w", path, err)
+	}
+	if rep.Command != cmd {
+		return transferReport{}, fmt.Errorf("отчёт %q относится к команде %q, а не %q", path, rep.Command, cmd)
+	}
+	if rep.Alias == "" || rep.Bucket == "" {
+		return transferReport{}, fmt.Errorf("в отчёте %q нет алиаса или бакета", path)
+	}
+	return rep, nil
+}
+
+func printFailed(failed []transfer.FailedItem) {
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Не переданы:")
+	for _, f := range failed {
+		fmt.Fprintf(os.Stderr, "  %v\n", f.Err)
+	}
+}
+
+func printRejected(rejected []transfer.FailedItem) {
+	if len(rejected) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Отклонены небезопасные ключи:")
+	for _, f := range rejected {
+		fmt.Fprintf(os.Stderr, "  %v\n", f.Err)
+	}
+}
+
+func printConflicts(conflicts []transfer.FailedItem) {
+	if len(conflicts) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Конфликты файл/каталог:")
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "  %v\n", c.Err)
+	}
+}
+
+func printWarnings(warns []string) {
+	for _, w := range warns {
+		fmt.Fprintf(os.Stderr, "предупреждение: %s\n", w)
+	}
+}
@@ -0,0 +1,466 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wolfsTail/s3cli/internal/human"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+// restorePlanRecord — строка плана undo-rm/restore-to (--dry-run) для --output
+type restorePlanRecord struct {
+	Action    string     `json:"action"` // undelete | restore | delete
+	Key       string     `json:"key"`
+	VersionID string     `json:"version_id,omitempty"` // undelete — снимаемый маркер, restore — возвращаемая версия
+	Time      *time.Time `json:"last_modified,omitempty"`
+}
+
+// restoreRecord — итог undo-rm/restore-to для --output
+type restoreRecord struct {
+	Status    string `json:"status"` // ok | partial
+	Restored  int    `json:"restored"`
+	Deleted   int    `json:"deleted"`
+	Unchanged int    `json:"unchanged"`
+	Failed    int    `json:"failed"`
+}
+
+// walkHistories — WalkVersions, собранный по ключам: fn получает все версии одного ключа
+// от новой к старой. Версии ключа идут подряд, поэтому в памяти только один ключ.
+func walkHistories(ctx context.Context, client *s3client.Client, bucket, prefix string, fn func([]s3client.VersionInfo) error) error {
+	var cur []s3client.VersionInfo
+	err := client.WalkVersions(ctx, bucket, prefix, func(v s3client.VersionInfo) error {
+		if len(cur) > 0 && cur[0].Key != v.Key {
+			if err := fn(cur); err != nil {
+				return err
+			}
+			cur = nil
+		}
+		cur = append(cur, v)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(cur) > 0 {
+		return fn(cur)
+	}
+	return nil
+}
+
+// versionedClient — клиент для бакета, где есть история версий; без неё восстанавливать не из чего
+func versionedClient(ctx context.Context, cfgPath string, sp s3Path, verbose bool) (*s3client.Client, int, error) {
+	client, code, err := clientFor(ctx, cfgPath, sp)
+	if err != nil {
+		return nil, code, err
+	}
+	v, err := client.GetVersioning(ctx, sp.Bucket)
+	if err != nil {
+		code, err := handleAWSError(err, verbose, fmt.Sprintf("Бакет не найден: %s", sp.Bucket), "Доступ запрещён")
+		return nil, code, err
+	}
+	if v.Status == "" {
+		return nil, 4, fmt.Errorf("в бакете %q версионирование не включалось — старых версий нет (s3cli version enable)", sp.Bucket)
+	}
+	return client, 0, nil
+}
+
+func runUndoRm(args []string, cfgPath string, verbose bool) (int, error) {
+	// undo-rm <alias>/<bucket>/<prefix?> [--since T] [--dry-run]
+	var (
+		since  time.Time
+		dryRun bool
+		pos    []string
+	)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--since":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --since требует время")
+			}
+			t, err := parseAge(args[i+1], time.Now())
+			if err != nil {
+				return 4, err
+			}
+			since = t
+			i++
+		case "--dry-run":
+			dryRun = true
+		case "-h", "--help":
+			fmt.Print(undoRmUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'undo-rm': %q\n\n%s", args[i], undoRmUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/prefix]\n\n%s", undoRmUsage())
+	}
+	sp, err := parseS3Path(pos[0])
+	if err != nil {
+		return 4, err
+	}
+
+	// без общего таймаута: обход истории большого префикса идёт дольше пары минут, прервать — Ctrl+C
+	ctx := rootCtx
+	client, code, err := versionedClient(ctx, cfgPath, sp, verbose)
+	if err != nil {
+		return code, err
+	}
+
+	// с каждого ключа снимаем маркеры удаления, лежащие сверху и поставленные после since.
+	// Если под ними нет обычной версии (объект удалили ещё раньше), ключ не трогаем.
+	// Маркеры снимаем пачками по ходу обхода, как find --delete: в памяти не больше пачки.
+	var (
+		rw                       *recordWriter
+		batch                    []s3client.VersionInfo
+		keys, planned, n, failed int
+	)
+	if dryRun && structured() {
+		rw = newRecordWriter("action", "key", "version_id", "last_modified")
+	}
+	flush := func() error {
+		deleted, err := client.DeleteVersions(ctx, sp.Bucket, batch)
+		n += deleted
+		failed += len(batch) - deleted
+		batch = batch[:0]
+		if errors.Is(err, s3client.ErrPartialDelete) {
+			// отказ по части маркеров — идём дальше, в итоге они посчитаны как failed
+			if !structured() {
+				fmt.Fprintln(os.Stderr, err.Error())
+			}
+			return nil
+		}
+		return err
+	}
+	err = walkHistories(ctx, client, sp.Bucket, sp.Key, func(vs []s3client.VersionInfo) error {
+		k := 0
+		for k < len(vs) && vs[k].DeleteMarker && vs[k].LastModified.After(since) {
+			k++
+		}
+		if k == 0 || k == len(vs) || vs[k].DeleteMarker {
+			return nil
+		}
+		keys++
+		planned += k
+		switch {
+		case rw != nil:
+			for _, m := range vs[:k] {
+				t := m.LastModified.UTC()
+				rw.write(restorePlanRecord{Action: "undelete", Key: m.Key, VersionID: m.VersionID, Time: &t})
+			}
+		case dryRun:
+			fmt.Printf("восстановить: %s (удалён %s)\n", vs[0].Key, human.Time(vs[0].LastModified))
+		default:
+			batch = append(batch, vs[:k]...)
+			if len(batch) >= 1000 {
+				return flush()
+			}
+		}
+		return nil
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	if rw != nil {
+		rw.finish(err)
+	}
+	if err != nil {
+		if !dryRun && n > 0 && !structured() {
+			fmt.Printf("Снято маркеров удаления: %d\n", n)
+		}
+		return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
+	}
+
+	if dryRun {
+		if structured() {
+			return 0, nil
+		}
+		fmt.Printf("План: восстановить %s, снять %s\n",
+			human.Count(int64(keys), "объект", "объекта", "объектов"),
+			human.Count(int64(planned), "маркер удаления", "маркера удаления", "маркеров удаления"))
+		return 0, nil
+	}
+
+	if structured() {
+		// deleted — снятые маркеры удаления
+		rec := restoreRecord{Status: "ok", Restored: keys, Deleted: n, Failed: failed}
+		if failed > 0 {
+			// какие ключи восстановились, по ответу пакетного удаления не видно — только маркеры
+			rec.Status, rec.Restored = "partial", 0
+		}
+		emitRecord(rec, "status", "restored", "deleted", "unchanged", "failed")
+		if failed > 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	fmt.Printf("Снято маркеров удаления: %d из %d\n", n, planned)
+	if failed > 0 {
+		return 1, fmt.Errorf("восстановление завершено с ошибками")
+	}
+	return 0, nil
+}
+
+// restoreAction — что restore-to сделает с одним ключом
+type restoreAction struct {
+	key     string
+	version s3client.VersionInfo // restore: версия, актуальная на момент at
+	delete  bool                 // ключа на момент at не было (с --delete)
+}
+
+func runRestoreTo(args []string, cfgPath string, verbose bool) (int, error) {
+	// restore-to <alias>/<bucket>/<prefix?> --at T [--delete] [--dry-run] [-j N]
+	var (
+		at         time.Time
+		withDelete bool
+		dryRun     bool
+		pos        []string
+	)
+	jobs := 4
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--at":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --at требует время")
+			}
+			t, err := parseAge(args[i+1], time.Now())
+			if err != nil {
+				return 4, err
+			}
+			at = t
+			i++
+		case "-j", "--jobs":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг -j требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return 4, fmt.Errorf("некорректное значение для -j: %q", args[i+1])
+			}
+			jobs = n
+			i++
+		case "--delete":
+			withDelete = true
+		case "--dry-run":
+			dryRun = true
+		case "-h", "--help":
+			fmt.Print(restoreToUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'restore-to': %q\n\n%s", args[i], restoreToUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/prefix]\n\n%s", restoreToUsage())
+	}
+	if at.IsZero() {
+		return 4, fmt.Errorf("нужно указать момент времени: --at 2026-10-01T12:00\n\n%s", restoreToUsage())
+	}
+	sp, err := parseS3Path(pos[0])
+	if err != nil {
+		return 4, err
+	}
+
+	ctx := rootCtx
+	client, code, err := versionedClient(ctx, cfgPath, sp, verbose)
+	if err != nil {
+		return code, err
+	}
+
+	var (
+		plan      []restoreAction
+		unchanged int
+		newer     int // ключи, появившиеся после at, без --delete
+	)
+	err = walkHistories(ctx, client, sp.Bucket, sp.Key, func(vs []s3client.VersionInfo) error {
+		// версия, актуальная на момент at, — самая новая из созданных не позже него
+		var then *s3client.VersionInfo
+		for i := range vs {
+			if !vs[i].LastModified.After(at) {
+				then = &vs[i]
+				break
+			}
+		}
+		now := vs[0]
+		switch {
+		case then == nil || then.DeleteMarker:
+			// тогда ключа не было
+			if now.DeleteMarker {
+				unchanged++
+			} else if withDelete {
+				plan = append(plan, restoreAction{key: now.Key, delete: true})
+			} else {
+				newer++
+			}
+		case then.VersionID == now.VersionID:
+			unchanged++
+		case !now.DeleteMarker && now.ETag == then.ETag && now.Size == then.Size:
+			// уже восстановлено (копия — новая версия с тем же содержимым)
+			unchanged++
+		default:
+			plan = append(plan, restoreAction{key: now.Key, version: *then})
+		}
+		return nil
+	})
+	if err != nil {
+		return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
+	}
+
+	restores, deletes := 0, 0
+	for _, a := range plan {
+		if a.delete {
+			deletes++
+		} else {
+			restores++
+		}
+	}
+
+	if dryRun {
+		if structured() {
+			rw := newRecordWriter("action", "key", "version_id", "last_modified")
+			for _, a := range plan {
+				if a.delete {
+					rw.write(restorePlanRecord{Action: "delete", Key: a.key})
+					continue
+				}
+				t := a.version.LastModified.UTC()
+				rw.write(restorePlanRecord{Action: "restore", Key: a.key, VersionID: a.version.VersionID, Time: &t})
+			}
+			rw.close()
+			return 0, nil
+		}
+		for _, a := range plan {
+			if a.delete {
+				fmt.Printf("удалить: %s\n", a.key)
+				continue
+			}
+			fmt.Printf("восстановить: %s (версия от %s)\n", a.key, human.Time(a.version.LastModified))
+		}
+		fmt.Printf("План: восстановить %d, удалить %d, без изменений %d\n", restores, deletes, unchanged)
+		if newer > 0 {
+			fmt.Printf("Появились позже --at и не тронуты: %d (удалить их — --delete)\n", newer)
+		}
+		return 0, nil
+	}
+
+	restored, failed := 0, 0
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	jobsCh := make(chan restoreAction)
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range jobsCh {
+				err := client.CopyVersion(ctx, sp.Bucket, a.key, a.version.VersionID, a.version.Size)
+				mu.Lock()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", a.key, err)
+					failed++
+				} else {
+					restored++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	var toDelete []string
+	for _, a := range plan {
+		if a.delete {
+			toDelete = append(toDelete, a.key)
+			continue
+		}
+		select {
+		case jobsCh <- a:
+		case <-ctx.Done():
+		}
+	}
+	close(jobsCh)
+	wg.Wait()
+	if ctx.Err() != nil {
+		return 1, ctx.Err()
+	}
+
+	deleted := 0
+	if len(toDelete) > 0 {
+		// в бакете с версионированием это маркеры удаления — ключи остаются в истории
+		n, err := client.DeleteKeys(ctx, sp.Bucket, toDelete)
+		deleted = n
+		failed += len(toDelete) - n
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+	}
+
+	if structured() {
+		rec := restoreRecord{Status: "ok", Restored: restored, Deleted: deleted, Unchanged: unchanged, Failed: failed}
+		if failed > 0 {
+			rec.Status = "partial"
+		}
+		emitRecord(rec, "status", "restored", "deleted", "unchanged", "failed")
+		if failed > 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	fmt.Printf("Восстановлено: %d, удалено: %d, без изменений: %d, ошибок: %d\n", restored, deleted, unchanged, failed)
+	if newer > 0 {
+		fmt.Printf("Появились позже --at и не тронуты: %d (удалить их — --delete)\n", newer)
+	}
+	if failed > 0 {
+		return 1, fmt.Errorf("восстановление завершено с ошибками")
+	}
+	return 0, nil
+}
+
+func undoRmUsage() string {
+	return `Использование:
+  s3cli undo-rm <alias>/<bucket>/<prefix?> [--since T] [--dry-run]
+
+Описание:
+  Отменяет удаление объектов в бакете с версионированием: там rm лишь ставит маркер
+  удаления поверх объекта, а undo-rm снимает такие маркеры, и прежняя версия снова
+  становится текущей.
+  --since T — снимать только маркеры, поставленные позже T: возраст (90s, 15m, 12h, 7d)
+  или дата (2025-01-02, 2025-01-02T15:04, RFC 3339). Без флага — все маркеры сверху.
+  Объекты, удалённые раньше T, остаются удалёнными.
+  --dry-run — только показать, что будет восстановлено.
+Пример:
+  s3cli undo-rm s3s7/fao_qa/reports/ --since 2h --dry-run
+`
+}
+
+func restoreToUsage() string {
+	return `Использование:
+  s3cli restore-to <alias>/<bucket>/<prefix?> --at T [--delete] [--dry-run] [-j N]
+
+Описание:
+  Возвращает объекты под префиксом в состояние на момент T (бакет должен быть
+  с версионированием). Для каждого ключа версия, бывшая текущей в момент T,
+  копируется на стороне сервера поверх ключа и снова становится текущей; все версии,
+  в том числе заменённые, остаются в истории.
+  --at T — момент времени: дата (2026-10-01T12:00, 2026-10-01, RFC 3339, локальное время)
+  или возраст (12h, 7d).
+  Объекты, удалённые после T, восстанавливаются; объекты, которых в момент T ещё
+  не было, по умолчанию не трогаются, --delete — удалить и их (ставится маркер удаления).
+  --dry-run — только показать план.
+  -j N — сколько объектов копировать параллельно (по умолчанию 4).
+Пример:
+  s3cli restore-to s3s7/fao_qa/reports/ --at 2026-10-01T12:00 --dry-run
+`
+}
@@ -0,0 +1,345 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/filter"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+	"github.com/wolfsTail/s3cli/internal/transfer"
+)
+
+func runSync(args []string, cfgPath string, verbose bool, showProgress bool, limitRate int64) (int, error) {
+	// sync <src> <dst> [-j N] [--retries N] [--delete] [--dry-run] [--compare mtime|etag]
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		fmt.Print(syncUsage())
+		return 0, nil
+	}
+	if len(args) < 2 {
+		return 4, fmt.Errorf("нужно указать источник и приёмник\n\n%s", syncUsage())
+	}
+
+	src := args[0]
+	dst := args[1]
+	jobs := 4
+	retries := -1
+	withDelete := false
+	dryRun := false
+	mode := transfer.CompareMtime
+	flt := filter.New()
+
+	for i := 2; i < len(args); i++ {
+		switch args[i] {
+		case "-j", "--jobs":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг -j требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return 4, fmt.Errorf("некорректное значение для -j: %q", args[i+1])
+			}
+			jobs = n
+			i++
+		case "--retries":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --retries требует число")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				return 4, fmt.Errorf("некорректное значение для --retries: %q", args[i+1])
+			}
+			retries = n
+			i++
+		case "--delete":
+			withDelete = true
+		case "--dry-run":
+			dryRun = true
+		case "--compare":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг --compare требует значение: mtime или etag")
+			}
+			switch args[i+1] {
+			case "mtime":
+				mode = transfer.CompareMtime
+			case "etag":
+				mode = transfer.CompareETag
+			default:
+				return 4, fmt.Errorf("некорректное значение для --compare: %q (ожидаю mtime или etag)", args[i+1])
+			}
+			i++
+		case "--include", "--exclude":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг %s требует шаблон", args[i])
+			}
+			if err := addFilter(flt, args[i], args[i+1]); err != nil {
+				return 4, err
+			}
+			i++
+		case "-h", "--help":
+			fmt.Print(syncUsage())
+			return 0, nil
+		default:
+			return 4, fmt.Errorf("неизвестный аргумент для sync: %q\n\n%s", args[i], syncUsage())
+		}
+	}
+
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+
+	srcRemote := isRemotePath(src, cfg)
+	dstRemote := isRemotePath(dst, cfg)
+	switch {
+	case srcRemote && dstRemote:
+		return 4, fmt.Errorf("синхронизация между двумя бакетами пока не поддерживается")
+	case !srcRemote && !dstRemote:
+		return 4, fmt.Errorf("один из путей должен быть вида alias/bucket/prefix/\n\n%s", syncUsage())
+	}
+
+	remote, local := dst, src
+	if srcRemote {
+		remote, local = src, dst
+	}
+	sp, err := parseS3Path(remote)
+	if err != nil {
+		return 4, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден", sp.Alias)
+		}
+		return 1, err
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 24*time.Hour)
+	defer cancel()
+
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+
+	limiter, err := rateLimiter(limitRate, alias)
+	if err != nil {
+		return 1, err
+	}
+
+	// .s3ignore локального каталога действует в обе стороны: исключённое не передаётся и не удаляется
+	if err := flt.LoadIgnoreFile(filepath.Join(local, filter.IgnoreFile)); err != nil {
+		return 1, err
+	}
+
+	prefix := sp.Key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	objects, err := client.ListAllObjects(ctx, sp.Bucket, prefix)
+	if err != nil {
+		return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+	}
+	remoteEntries := make([]transfer.SyncEntry, 0, len(objects))
+	for _, o := range objects {
+		if strings.HasSuffix(o.Key, "/") {
+			continue // маркеры «папок»
+		}
+		rel := strings.TrimPrefix(o.Key, prefix)
+		if !flt.Match(rel) {
+			continue
+		}
+		remoteEntries = append(remoteEntries, transfer.SyncEntry{
+			Rel:     rel,
+			Size:    o.Size,
+			ModTime: o.LastModified,
+			ETag:    o.ETag,
+		})
+	}
+
+	var localEntries []transfer.SyncEntry
+	fi, err := os.Stat(local)
+	switch {
+	case err == nil && !fi.IsDir():
+		return 4, fmt.Errorf("sync работает только с каталогами, %q — не каталог", local)
+	case err == nil:
+		localEntries, err = transfer.WalkLocal(local, flt)
+		if err != nil {
+			return 1, err
+		}
+	case os.IsNotExist(err) && srcRemote:
+		// каталог-приёмник будет создан
+	default:
+		return 1, fmt.Errorf("не удалось получить информацию о %q: %w", local, err)
+	}
+
+	var plan transfer.SyncPlan
+	if srcRemote {
+		plan, err = transfer.PlanSync(remoteEntries, localEntries, mode, withDelete)
+	} else {
+		plan, err = transfer.PlanSync(localEntries, remoteEntries, mode, withDelete)
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	if dryRun {
+		if structured() {
+			action := "upload"
+			if srcRemote {
+				action = "download"
+			}
+			rw := newRecordWriter("action", "path")
+			for _, e := range plan.Copy {
+				rw.write(syncPlanRecord{Action: action, Path: e.Rel})
+			}
+			for _, e := range plan.Delete {
+				rw.write(syncPlanRecord{Action: "delete", Path: e.Rel})
+			}
+			rw.close()
+			return 0, nil
+		}
+		verb := "загрузить"
+		if srcRemote {
+			verb = "скачать"
+		}
+		for _, e := range plan.Copy {
+			fmt.Printf("%s: %s\n", verb, e.Rel)
+		}
+		for _, e := range plan.Delete {
+			fmt.Printf("удалить: %s\n", e.Rel)
+		}
+		fmt.Printf("План: передать %d, удалить %d, без изменений %d\n", len(plan.Copy), len(plan.Delete), plan.Unchanged)
+		return 0, nil
+	}
+
+	copied, deleted, failed := 0, 0, 0
+	if srcRemote {
+		if len(plan.Copy) > 0 {
+			objs := make([]s3client.ObjectInfo, 0, len(plan.Copy))
+			for _, e := range plan.Copy {
+				objs = append(objs, s3client.ObjectInfo{Key: prefix + e.Rel, Size: e.Size, LastModified: e.ModTime})
+			}
+			stats, err := transfer.DownloadObjects(ctx, client.S3, sp.Bucket, objs, prefix, local, transfer.GetOptions{
+				Jobs:         jobs,
+				ShowProgress: showProgress,
+				Retry:        retryPolicy(retries, alias),
+				Limiter:      limiter,
+			})
+			if err != nil {
+				return 1, err
+			}
+			printWarnings(stats.Warnings)
+			printRejected(stats.Rejected)
+			copied += stats.Downloaded
+			failed += stats.Failed + len(stats.Rejected)
+		}
+		for _, e := range plan.Delete {
+			if err := os.Remove(e.Local); err != nil {
+				fmt.Fprintf(os.Stderr, "не удалось удалить %q: %v\n", e.Local, err)
+				failed++
+				continue
+			}
+			deleted++
+		}
+	} else {
+		if len(plan.Copy) > 0 {
+			journalDir, err := config.UploadsDir()
+			if err != nil {
+				return 1, err
+			}
+			items := make([]transfer.UploadItem, 0, len(plan.Copy))
+			for _, e := range plan.Copy {
+				items = append(items, transfer.UploadItem{Local: e.Local, Key: prefix + e.Rel})
+			}
+			stats, err := transfer.UploadFiles(ctx, client.S3, sp.Bucket, items, transfer.PutOptions{
+				Jobs:         jobs,
+				ShowProgress: showProgress,
+				Resume:       true,
+				JournalDir:   journalDir,
+				Retry:        retryPolicy(retries, alias),
+				Limiter:      limiter,
+			})
+			if err != nil {
+				return handleAWSError(err, verbose, "Не найдено", "Доступ запрещён")
+			}
+			copied += stats.Uploaded
+			failed += stats.Failed
+		}
+		if len(plan.Delete) > 0 {
+			keys := make([]string, 0, len(plan.Delete))
+			for _, e := range plan.Delete {
+				keys = append(keys, prefix+e.Rel)
+			}
+			n, err := client.DeleteKeys(ctx, sp.Bucket, keys)
+			deleted += n
+			failed += len(keys) - n
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+			}
+		}
+	}
+
+	if structured() {
+		rec := syncRecord{Status: "ok", Transferred: copied, Deleted: deleted, Unchanged: plan.Unchanged, Failed: failed}
+		if failed > 0 {
+			rec.Status = "partial"
+		}
+		emitRecord(rec, "status", "transferred", "deleted", "unchanged", "failed")
+		if failed > 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	fmt.Printf("Передано: %d, удалено: %d, без изменений: %d, ошибок: %d\n",
+		copied, deleted, plan.Unchanged, failed)
+	if failed > 0 {
+		return 1, fmt.Errorf("синхронизация завершена с ошибками")
+	}
+	return 0, nil
+}
+
+// syncPlanRecord — строка плана sync --dry-run для --output
+type syncPlanRecord struct {
+	Action string `json:"action"` // upload | download | delete
+	Path   string `json:"path"`
+}
+
+// syncRecord — итог sync для --output
+type syncRecord struct {
+	Status      string `json:"status"` // ok | partial
+	Transferred int    `json:"transferred"`
+	Deleted     int    `json:"deleted"`
+	Unchanged   int    `json:"unchanged"`
+	Failed      int    `json:"failed"`
+}
+
+func syncUsage() string {
+	return `Использование:
+  s3cli sync <src> <dst> [-j N] [--retries N] [--delete] [--dry-run] [--compare mtime|etag]
+             [--include GLOB] [--exclude GLOB]
+
+Описание:
+  Односторонняя синхронизация каталога с префиксом или префикса с каталогом.
+  Одна из сторон — локальный каталог, другая — alias/bucket/prefix/.
+  Передаются только новые и изменённые файлы.
+  -j N — число параллельных передач (по умолчанию 4).
+  --retries N — сколько раз повторять файл при временных ошибках.
+  --compare mtime — сравнивать размер и время изменения (по умолчанию);
+  --compare etag — сравнивать размер и ETag (MD5 локального файла).
+  --delete — удалить на приёмнике то, чего нет в источнике.
+  --dry-run — только показать план, ничего не менять.
+  --include GLOB / --exclude GLOB — фильтры, как у put. .s3ignore локального каталога
+  учитывается в обе стороны: исключённое не передаётся и не удаляется.
+
+Примеры:
+  s3cli sync ./site s3s7/web/site/ --delete
+  s3cli sync s3s7/backups/db/ ./db --dry-run
+`
+}
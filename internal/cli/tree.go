@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/filter"
+	"github.com/wolfsTail/s3cli/internal/human"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+// parseMaxDepth — значение --max-depth: положительное число уровней
+func parseMaxDepth(v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("некорректное значение для --max-depth: %q (нужно число от 1)", v)
+	}
+	return n, nil
+}
+
+// cutDepth — первые depth сегментов пути rel; ok=false, если rel не глубже depth
+func cutDepth(rel string, depth int) (string, bool) {
+	idx := 0
+	for i := 0; i < depth; i++ {
+		j := strings.IndexByte(rel[idx:], '/')
+		if j < 0 {
+			return rel, false
+		}
+		idx += j + 1
+	}
+	if idx == len(rel) {
+		// маркер "folder/" ровно на границе — это сам каталог
+		return rel, false
+	}
+	return rel[:idx], true
+}
+
+// lsRecursive — ls -r: объекты печатаются по мере листинга, весь список в памяти не держим.
+// Ключи приходят по возрастанию, поэтому всё содержимое одного каталога идёт подряд —
+// каталог глубже maxDepth печатается одной строкой, как только встретился.
+func lsRecursive(ctx context.Context, client *s3client.Client, bucket, prefix string, flt *filter.Filter, maxDepth int, verbose bool) (int, error) {
+	var rw *recordWriter
+	if structured() {
+		rw = newRecordWriter(objectCols...)
+	} else {
+		fmt.Println("date               size       name")
+	}
+
+	printed := 0
+	lastDir := ""
+	err := client.WalkObjects(ctx, bucket, prefix, func(o s3client.ObjectInfo) error {
+		rel := strings.TrimPrefix(o.Key, prefix)
+		if rel == "" || !flt.Match(rel) {
+			return nil
+		}
+		if maxDepth > 0 {
+			if dir, deeper := cutDepth(rel, maxDepth); deeper {
+				if dir != lastDir {
+					lastDir = dir
+					printed++
+					if rw != nil {
+						rw.write(objectRecord{Type: "prefix", Bucket: bucket, Key: prefix + dir, Name: dir})
+					} else {
+						fmt.Printf("%-16s  %10s  %s\n", "-", "-", dir)
+					}
+				}
+				return nil
+			}
+		}
+		if strings.HasSuffix(rel, "/") {
+			// маркер "folder/" напечатан своей строкой: его содержимое дальше не должно её повторить
+			lastDir = rel
+		}
+		printed++
+		if rw != nil {
+			rw.write(newObjectRecord(bucket, prefix, o))
+		} else {
+			fmt.Printf("%-16s  %10s  %s\n", human.Time(o.LastModified), human.Bytes(o.Size), rel)
+		}
+		return nil
+	})
+	if rw != nil {
+		rw.finish(err)
+	}
+	if err != nil {
+		return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
+	}
+	if printed == 0 && rw == nil {
+		fmt.Println("Увы, ничего нет")
+	}
+	return 0, nil
+}
+
+func runTree(args []string, cfgPath string, verbose bool) (int, error) {
+	// tree <alias>/<bucket>/<prefix?> [--max-depth N] [--include GLOB] [--exclude GLOB]
+	maxDepth := 0
+	flt := filter.New()
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--max-depth", "-L":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг %s требует число", args[i])
+			}
+			n, err := parseMaxDepth(args[i+1])
+			if err != nil {
+				return 4, err
+			}
+			maxDepth = n
+			i++
+		case "--include", "--exclude":
+			if i+1 >= len(args) {
+				return 4, fmt.Errorf("флаг %s требует шаблон", args[i])
+			}
+			if err := addFilter(flt, args[i], args[i+1]); err != nil {
+				return 4, err
+			}
+			i++
+		case "-h", "--help":
+			fmt.Print(treeUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(args[i], "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'tree': %q\n\n%s", args[i], treeUsage())
+			}
+			pos = append(pos, args[i])
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket[/prefix]\n\n%s", treeUsage())
+	}
+
+	sp, err := parseS3Path(pos[0])
+	if err != nil {
+		return 4, err
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", sp.Alias)
+		}
+		return 1, err
+	}
+
+	// без общего таймаута: листинг большого бакета идёт дольше пары минут, прервать — Ctrl+C
+	ctx := rootCtx
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+
+	// "logs" — каталог logs/, а не все ключи на "logs" (logs2/, logs.tar)
+	prefix := sp.Key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	t := &treePrinter{maxDepth: maxDepth, stack: []*treeDir{{}}, bucket: sp.Bucket, prefix: prefix}
+	if structured() {
+		t.rw = newRecordWriter(objectCols...)
+	} else {
+		fmt.Printf("s3://%s/%s\n", sp.Bucket, prefix)
+	}
+
+	err = client.WalkObjects(ctx, sp.Bucket, prefix, func(o s3client.ObjectInfo) error {
+		rel := strings.TrimPrefix(o.Key, prefix)
+		if rel == "" || !flt.Match(rel) {
+			return nil
+		}
+		t.add(rel, o)
+		return nil
+	})
+	if err != nil {
+		if t.rw != nil {
+			t.rw.finish(err)
+		}
+		return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
+	}
+	t.finish()
+	return 0, nil
+}
+
+// treeDir — открытый каталог дерева; count/size копятся, пока идут его ключи
+type treeDir struct {
+	name  string // сегмент с "/" на конце, у корня пусто
+	path  string // путь от префикса, с "/" на конце
+	count int64
+	size  int64
+}
+
+// treePrinter — рисует дерево по потоку ключей в порядке возрастания.
+// Итоги каталога известны только после его последнего ключа, поэтому у раскрытого
+// каталога они печатаются строкой в конце, а каталог на глубине --max-depth
+// печатается одной строкой с итогами сразу при закрытии. В json/csv каталог —
+// запись type=prefix с итогами, она тоже выводится при закрытии, после содержимого.
+type treePrinter struct {
+	rw       *recordWriter // nil — рисуем текстом
+	bucket   string
+	prefix   string
+	maxDepth int
+	stack    []*treeDir // stack[0] — корень (сам префикс)
+	full     []string   // каталоги предыдущего ключа целиком, и глубже --max-depth
+	dirs     int64      // сколько всего каталогов встретилось
+}
+
+func (t *treePrinter) add(rel string, o s3client.ObjectInfo) {
+	segs := strings.Split(rel, "/")
+	dirs, file := segs[:len(segs)-1], segs[len(segs)-1]
+
+	// каталоги считаем по полному пути: каждый встречается одним непрерывным куском
+	same := 0
+	for same < len(dirs) && same < len(t.full) && t.full[same] == dirs[same] {
+		same++
+	}
+	t.dirs += int64(len(dirs) - same)
+	t.full = dirs
+
+	if t.maxDepth > 0 && len(dirs) > t.maxDepth {
+		dirs = dirs[:t.maxDepth]
+		file = ""
+	}
+
+	// закрыть каталоги, из которых ключи ушли
+	common := 0
+	for common < len(dirs) && common+1 < len(t.stack) && t.stack[common+1].name == dirs[common]+"/" {
+		common++
+	}
+	for len(t.stack) > common+1 {
+		t.close()
+	}
+	// открыть новые
+	for _, d := range dirs[common:] {
+		parent := t.stack[len(t.stack)-1]
+		dir := &treeDir{name: d + "/", path: parent.path + d + "/"}
+		t.stack = append(t.stack, dir)
+		if t.rw == nil && !t.collapsed(len(t.stack)-1) {
+			fmt.Printf("%10s  %s%s\n", "", t.indent(len(t.stack)-1), dir.name)
+		}
+	}
+
+	if strings.HasSuffix(rel, "/") {
+		// маркер "folder/" — сам каталог, не объект
+		return
+	}
+	cur := t.stack[len(t.stack)-1]
+	cur.count++
+	cur.size += o.Size
+	if file == "" || t.collapsed(len(t.stack)-1) {
+		return
+	}
+	if t.rw != nil {
+		t.rw.write(newObjectRecord(t.bucket, t.prefix, o))
+		return
+	}
+	fmt.Printf("%10s  %s%s\n", human.Bytes(o.Size), t.indent(len(t.stack)), file)
+}
+
+// collapsed — каталог на глубине maxDepth показывается свёрнутым
+func (t *treePrinter) collapsed(depth int) bool {
+	return t.maxDepth > 0 && depth >= t.maxDepth
+}
+
+func (t *treePrinter) indent(depth int) string {
+	return strings.Repeat("  ", depth-1)
+}
+
+// close — закрыть самый глубокий каталог и передать итоги родителю
+func (t *treePrinter) close() {
+	depth := len(t.stack) - 1
+	dir := t.stack[depth]
+	t.stack = t.stack[:depth]
+	parent := t.stack[depth-1]
+	parent.count += dir.count
+	parent.size += dir.size
+
+	if t.rw != nil {
+		t.rw.write(objectRecord{Type: "prefix", Bucket: t.bucket, Key: t.prefix + dir.path, Name: dir.path, Size: dir.size, Objects: dir.count})
+		return
+	}
+	if t.collapsed(depth) {
+		fmt.Printf("%10s  %s%s (%s)\n", human.Bytes(dir.size), t.indent(depth), dir.name,
+			human.Count(dir.count, "объект", "объекта", "объектов"))
+		return
+	}
+	fmt.Printf("%10s  %s└ %s: %s\n", human.Bytes(dir.size), t.indent(depth+1), dir.name,
+		human.Count(dir.count, "объект", "объекта", "объектов"))
+}
+
+func (t *treePrinter) finish() {
+	for len(t.stack) > 1 {
+		t.close()
+	}
+	root := t.stack[0]
+	if t.rw != nil {
+		// последняя запись — сам префикс с общими итогами
+		t.rw.write(objectRecord{Type: "prefix", Bucket: t.bucket, Key: t.prefix, Size: root.size, Objects: root.count})
+		t.rw.close()
+		return
+	}
+	fmt.Printf("Итого: %s в %s, %s\n",
+		human.Count(root.count, "объект", "объекта", "объектов"),
+		human.Count(t.dirs, "каталоге", "каталогах", "каталогах"),
+		human.Bytes(root.size))
+}
+
+func treeUsage() string {
+	return `Использование:
+  s3cli tree <alias>/<bucket>/<prefix?> [--max-depth N] [--include GLOB] [--exclude GLOB]
+
+Описание:
+  Рисует иерархию «папок» под префиксом с числом объектов и суммарным размером
+  каждой. Вывод идёт по мере листинга, весь список в памяти не держится, поэтому
+  итоги каталога печатаются строкой «└ имя/: N объектов» после его содержимого.
+  --max-depth N (-L N) — раскрывать не глубже N уровней; каталоги на N-м уровне
+  показываются одной строкой с итогами.
+  --include GLOB / --exclude GLOB — фильтры по пути относительно префикса,
+  повторяемые (подробнее: s3cli put --help).
+Пример:
+  s3cli tree s3s7/fao_qa/reports/ --max-depth 2
+`
+}
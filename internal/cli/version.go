@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wolfsTail/s3cli/internal/config"
+	"github.com/wolfsTail/s3cli/internal/filter"
+	"github.com/wolfsTail/s3cli/internal/human"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+// versioningRecord — итог version enable|suspend|info для --output
+type versioningRecord struct {
+	Bucket    string `json:"bucket"`
+	Status    string `json:"status"` // enabled | suspended | off
+	MFADelete string `json:"mfa_delete,omitempty"`
+}
+
+// versionRecord — версия или маркер удаления в ls --versions;
+// type — version, delete_marker или prefix
+type versionRecord struct {
+	Type         string     `json:"type"`
+	Bucket       string     `json:"bucket"`
+	Key          string     `json:"key"`
+	Name         string     `json:"name,omitempty"`
+	VersionID    string     `json:"version_id,omitempty"`
+	IsLatest     bool       `json:"is_latest"`
+	Size         int64      `json:"size"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	ETag         string     `json:"etag,omitempty"`
+}
+
+var versionCols = []string{"type", "bucket", "key", "name", "version_id", "is_latest", "size", "last_modified", "etag"}
+
+func newVersionRecord(bucket, prefix string, v s3client.VersionInfo) versionRecord {
+	rec := versionRecord{
+		Type:      "version",
+		Bucket:    bucket,
+		Key:       v.Key,
+		Name:      strings.TrimPrefix(v.Key, prefix),
+		VersionID: v.VersionID,
+		IsLatest:  v.IsLatest,
+		Size:      v.Size,
+		ETag:      v.ETag,
+	}
+	if v.DeleteMarker {
+		rec.Type = "delete_marker"
+	}
+	if !v.LastModified.IsZero() {
+		t := v.LastModified.UTC()
+		rec.LastModified = &t
+	}
+	return rec
+}
+
+// versioningStatus — статус версионирования словами для вывода
+func versioningStatus(s string) string {
+	switch s {
+	case "Enabled":
+		return "enabled"
+	case "Suspended":
+		return "suspended"
+	}
+	return "off"
+}
+
+func runVersion(args []string, cfgPath string, verbose bool) (int, error) {
+	// version enable|suspend|info <alias>/<bucket>
+	if len(args) == 0 {
+		return 4, fmt.Errorf("нужно указать подкоманду: enable, suspend или info\n\n%s", versionUsage())
+	}
+	action := args[0]
+	switch action {
+	case "enable", "suspend", "info":
+	case "-h", "--help", "help":
+		fmt.Print(versionUsage())
+		return 0, nil
+	default:
+		return 4, fmt.Errorf("неизвестная подкоманда version: %q\n\n%s", action, versionUsage())
+	}
+	var pos []string
+	for _, a := range args[1:] {
+		switch a {
+		case "-h", "--help":
+			fmt.Print(versionUsage())
+			return 0, nil
+		default:
+			if strings.HasPrefix(a, "-") || len(pos) > 0 {
+				return 4, fmt.Errorf("лишний аргумент для 'version %s': %q\n\n%s", action, a, versionUsage())
+			}
+			pos = append(pos, a)
+		}
+	}
+	if len(pos) == 0 {
+		return 4, fmt.Errorf("нужно указать путь вида alias/bucket\n\n%s", versionUsage())
+	}
+
+	sp, err := parseBucketPath(pos[0], "version "+action)
+	if err != nil {
+		return 4, err
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return 1, err
+	}
+	alias, err := cfg.GetAlias(sp.Alias)
+	if err != nil {
+		if errors.Is(err, config.ErrAliasNotFound) {
+			return 2, fmt.Errorf("ошибка: алиас %q не найден. Посмотрите 's3cli alias ls'", sp.Alias)
+		}
+		return 1, err
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 2*time.Minute)
+	defer cancel()
+
+	client, err := s3client.New(ctx, alias)
+	if err != nil {
+		return 1, err
+	}
+
+	if action != "info" {
+		if err := client.SetVersioning(ctx, sp.Bucket, action == "enable"); err != nil {
+			return handleAWSError(err, verbose, fmt.Sprintf("Бакет не найден: %s", sp.Bucket), "Доступ запрещён")
+		}
+	}
+	v, err := client.GetVersioning(ctx, sp.Bucket)
+	if err != nil {
+		return handleAWSError(err, verbose, fmt.Sprintf("Бакет не найден: %s", sp.Bucket), "Доступ запрещён")
+	}
+
+	rec := versioningRecord{Bucket: sp.Bucket, Status: versioningStatus(v.Status), MFADelete: v.MFADelete}
+	if structured() {
+		emitRecord(rec, "bucket", "status", "mfa_delete")
+		return 0, nil
+	}
+	switch rec.Status {
+	case "enabled":
+		fmt.Printf("Версионирование бакета %q включено.\n", sp.Bucket)
+	case "suspended":
+		fmt.Printf("Версионирование бакета %q приостановлено: новые версии не создаются, старые сохранены.\n", sp.Bucket)
+	default:
+		fmt.Printf("Версионирование бакета %q не включалось.\n", sp.Bucket)
+	}
+	if v.MFADelete != "" {
+		fmt.Printf("MFA Delete: %s\n", v.MFADelete)
+	}
+	return 0, nil
+}
+
+// lsVersions — ls --versions: все версии и маркеры удаления. Без -r — один уровень,
+// как обычный ls; с -r — всё под префиксом, по мере листинга.
+func lsVersions(ctx context.Context, client *s3client.Client, bucket, prefix string, recursive bool, flt *filter.Filter, verbose bool) (int, error) {
+	var rw *recordWriter
+	if structured() {
+		rw = newRecordWriter(versionCols...)
+	} else {
+		fmt.Printf("%-16s  %10s    %-32s  %s\n", "date", "size", "version", "name")
+	}
+
+	printed := 0
+	show := func(v s3client.VersionInfo) {
+		printed++
+		if rw != nil {
+			rw.write(newVersionRecord(bucket, prefix, v))
+			return
+		}
+		size := human.Bytes(v.Size)
+		if v.DeleteMarker {
+			size = "удалён"
+		}
+		// * — текущая версия
+		mark := " "
+		if v.IsLatest {
+			mark = "*"
+		}
+		fmt.Printf("%-16s  %10s  %s %-32s  %s\n", human.Time(v.LastModified), size, mark, v.VersionID, strings.TrimPrefix(v.Key, prefix))
+	}
+
+	if recursive {
+		err := client.WalkVersions(ctx, bucket, prefix, func(v s3client.VersionInfo) error {
+			if rel := strings.TrimPrefix(v.Key, prefix); rel != "" && flt.Match(rel) {
+				show(v)
+			}
+			return nil
+		})
+		if rw != nil {
+			rw.finish(err)
+		}
+		if err != nil {
+			return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
+		}
+	} else {
+		folders, versions, err := client.ListVersionsOneLevel(ctx, bucket, prefix)
+		if err != nil {
+			return handleAWSError(err, verbose, "Объекты не найдены", "Доступ запрещён")
+		}
+		for _, f := range folders {
+			printed++
+			if rw != nil {
+				rw.write(versionRecord{Type: "prefix", Bucket: bucket, Key: f, Name: strings.TrimPrefix(f, prefix)})
+				continue
+			}
+			fmt.Printf("%-16s  %10s    %-32s  %s\n", "-", "-", "", strings.TrimPrefix(f, prefix))
+		}
+		for _, v := range versions {
+			show(v)
+		}
+		if rw != nil {
+			rw.close()
+		}
+	}
+	if printed == 0 && rw == nil {
+		fmt.Println("Увы, ничего нет")
+	}
+	return 0, nil
+}
+
+func versionUsage() string {
+	return `Использование:
+  s3cli version enable <alias>/<bucket>
+  s3cli version suspend <alias>/<bucket>
+  s3cli version info <alias>/<bucket>
+
+Описание:
+  Управляет версионированием бакета.
+  enable — включить: каждая перезапись и удаление сохраняют прежнюю версию.
+  suspend — приостановить: новые версии не создаются, уже созданные остаются.
+  Выключить версионирование совсем S3 не позволяет.
+  info — текущее состояние: enabled, suspended или off (не включалось).
+  Версии смотреть — s3cli ls --versions, достать старую — get/cat/stat --version-id,
+  удалить навсегда — rm --version-id.
+Пример:
+  s3cli version enable s3s7/fao_qa
+`
+}
@@ -16,6 +16,10 @@ type Alias struct {
 	SecretKey string `yaml:"secret_key"`
 	Secure    bool   `yaml:"secure"`
 	PathStyle bool   `yaml:"path_style"`
+	// Retries — сколько раз повторять файл/объект при временных ошибках (0 — по умолчанию)
+	Retries int `yaml:"retries,omitempty"`
+	// LimitRate — ограничение скорости передачи, например "20MiB/s" (пусто — без ограничения)
+	LimitRate string `yaml:"limit_rate,omitempty"`
 }
 
 type Config struct {
@@ -28,12 +32,29 @@ var (
 	ErrInvalidAlias  = errors.New("некорректное имя алиаса")
 )
 
-func DefaultPath() (string, error) {
+func DefaultDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("не удалось определить домашний каталог: %w", err)
 	}
-	return filepath.Join(home, ".s3cli", "config.yaml"), nil
+	return filepath.Join(home, ".s3cli"), nil
+}
+
+func DefaultPath() (string, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// UploadsDir — каталог журналов незавершённых multipart-загрузок
+func UploadsDir() (string, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "uploads"), nil
 }
 
 func Load(path string) (*Config, error) {
@@ -0,0 +1,201 @@
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFile — имя файла с шаблонами исключений в корне загружаемого каталога
+const IgnoreFile = ".s3ignore"
+
+type rule struct {
+	re      *regexp.Regexp
+	include bool
+}
+
+// Filter — набор правил --include/--exclude и строк .s3ignore.
+// Пути сравниваются относительно корня передачи, через "/".
+// Из флагов побеждает последнее совпавшее правило; .s3ignore слабее любого флага.
+// Если задан хоть один --include, всё, что не совпало ни с чем, исключается.
+// Нулевой *Filter пропускает всё.
+type Filter struct {
+	flags  []rule
+	ignore []rule
+	// onlyIncluded — был хотя бы один --include
+	onlyIncluded bool
+}
+
+func New() *Filter {
+	return &Filter{}
+}
+
+// Include — добавить --include
+func (f *Filter) Include(pattern string) error {
+	r, err := compile(pattern, true)
+	if err != nil {
+		return err
+	}
+	f.flags = append(f.flags, r)
+	f.onlyIncluded = true
+	return nil
+}
+
+// Exclude — добавить --exclude
+func (f *Filter) Exclude(pattern string) error {
+	r, err := compile(pattern, false)
+	if err != nil {
+		return err
+	}
+	f.flags = append(f.flags, r)
+	return nil
+}
+
+// LoadIgnoreFile — прочитать шаблоны в стиле .gitignore: по одному в строке,
+// # — комментарий, !шаблон — вернуть ранее исключённое. Отсутствие файла — не ошибка.
+func (f *Filter) LoadIgnoreFile(path string) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("не удалось открыть %q: %w", path, err)
+	}
+	defer fh.Close()
+
+	sc := bufio.NewScanner(fh)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		include := false
+		if strings.HasPrefix(line, "!") {
+			include = true
+			line = line[1:]
+		}
+		r, err := compile(line, include)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		f.ignore = append(f.ignore, r)
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения %q: %w", path, err)
+	}
+	return nil
+}
+
+// Empty — правил нет, фильтр пропускает всё
+func (f *Filter) Empty() bool {
+	return f == nil || (len(f.flags) == 0 && len(f.ignore) == 0)
+}
+
+// Match — проходит ли файл с относительным путём rel
+func (f *Filter) Match(rel string) bool {
+	if f.Empty() {
+		return true
+	}
+	if r, ok := lastMatch(f.flags, rel); ok {
+		return r.include
+	}
+	// "!шаблон" в .s3ignore только отменяет исключение, но не включает сверх --include
+	if r, ok := lastMatch(f.ignore, rel); ok && !r.include {
+		return false
+	}
+	return !f.onlyIncluded
+}
+
+// SkipDir — можно ли не заходить в каталог rel целиком: он исключён,
+// а правил, способных вернуть что-то внутри, нет
+func (f *Filter) SkipDir(rel string) bool {
+	if f.Empty() {
+		return false
+	}
+	for _, rs := range [][]rule{f.flags, f.ignore} {
+		for _, r := range rs {
+			if r.include {
+				return false
+			}
+		}
+	}
+	// "/" на конце, чтобы сработали и шаблоны каталогов вида node_modules/
+	return !f.Match(rel + "/")
+}
+
+func lastMatch(rules []rule, rel string) (rule, bool) {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].re.MatchString(rel) {
+			return rules[i], true
+		}
+	}
+	return rule{}, false
+}
+
+// compile — glob в регулярное выражение. "*" — любые символы, кроме "/", "?" — один символ,
+// "[abc]" — класс ("[!abc]" — отрицание), "**" — любое число каталогов.
+// Шаблон без "/" совпадает с именем на любой глубине, с "/" в начале — только от корня.
+// Шаблон на "/" означает каталог. Совпадение с каталогом захватывает всё внутри.
+func compile(pattern string, include bool) (rule, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" || p == "/" {
+		return rule{}, fmt.Errorf("пустой шаблон фильтра")
+	}
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.HasPrefix(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored && !strings.Contains(p, "/") {
+		b.WriteString("(?:.*/)?")
+	}
+	rs := []rune(p)
+	for i := 0; i < len(rs); i++ {
+		c := rs[i]
+		switch c {
+		case '*':
+			if i+1 < len(rs) && rs[i+1] == '*' {
+				i++
+				if i+1 < len(rs) && rs[i+1] == '/' {
+					// "**/" — ноль или больше каталогов
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexRune(string(rs[i+1:]), ']')
+			if end < 0 {
+				return rule{}, fmt.Errorf("незакрытая [ в шаблоне %q", pattern)
+			}
+			class := string(rs[i+1:])[:end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += len([]rune(class)) + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return rule{}, fmt.Errorf("некорректный шаблон %q: %w", pattern, err)
+	}
+	return rule{re: re, include: include}, nil
+}
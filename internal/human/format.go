@@ -2,6 +2,8 @@ package human
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -27,9 +29,60 @@ func Bytes(n int64) string {
 	}
 }
 
+// Count — число с существительным в нужной форме: Count(3, "объект", "объекта", "объектов")
+func Count(n int64, one, few, many string) string {
+	form := many
+	switch m10, m100 := n%10, n%100; {
+	case m10 == 1 && m100 != 11:
+		form = one
+	case m10 >= 2 && m10 <= 4 && (m100 < 12 || m100 > 14):
+		form = few
+	}
+	return fmt.Sprintf("%d %s", n, form)
+}
+
 func Time(t time.Time) string {
 	if t.IsZero() {
 		return "-"
 	}
 	return t.Format("2025-01-02 15:22")
 }
+
+// ParseBytes — размер вида 512, 64K, 20MiB, 1.5G. K/M/G/T и KiB/MiB/... — степени 1024,
+// KB/MB/GB/TB — степени 1000.
+func ParseBytes(s string) (int64, error) {
+	str := strings.TrimSpace(s)
+	i := 0
+	for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.') {
+		i++
+	}
+	num, unit := str[:i], strings.ToUpper(strings.TrimSpace(str[i:]))
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("некорректный размер: %q", s)
+	}
+	var mul float64
+	switch unit {
+	case "", "B":
+		mul = 1
+	case "K", "KIB":
+		mul = 1 << 10
+	case "M", "MIB":
+		mul = 1 << 20
+	case "G", "GIB":
+		mul = 1 << 30
+	case "T", "TIB":
+		mul = 1 << 40
+	case "KB":
+		mul = 1e3
+	case "MB":
+		mul = 1e6
+	case "GB":
+		mul = 1e9
+	case "TB":
+		mul = 1e12
+	default:
+		return 0, fmt.Errorf("некорректный размер: %q", s)
+	}
+	return int64(v * mul), nil
+}
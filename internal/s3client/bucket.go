@@ -0,0 +1,123 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type BucketInfo struct {
+	Name         string
+	CreationDate time.Time
+}
+
+func (c *Client) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
+	var res []BucketInfo
+	p := s3.NewListBucketsPaginator(c.S3, &s3.ListBucketsInput{})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка листинга бакетов: %w", err)
+		}
+		for _, b := range out.Buckets {
+			if b.Name == nil {
+				continue
+			}
+			res = append(res, BucketInfo{Name: aws.ToString(b.Name), CreationDate: derefTime(b.CreationDate)})
+		}
+	}
+	return res, nil
+}
+
+// CreateBucket — создать бакет. region пустой — регион алиаса; us-east-1 в LocationConstraint
+// не передаётся, S3 такого не принимает. withLock включает Object Lock (и версионирование вместе с ним).
+func (c *Client) CreateBucket(ctx context.Context, bucket, region string, withLock bool) error {
+	in := &s3.CreateBucketInput{Bucket: aws.String(bucket)}
+	if region == "" {
+		region = c.S3.Options().Region
+	}
+	if region != "" && region != "us-east-1" {
+		in.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(region),
+		}
+	}
+	if withLock {
+		in.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
+	if _, err := c.S3.CreateBucket(ctx, in); err != nil {
+		return fmt.Errorf("ошибка создания бакета: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteBucket(ctx context.Context, bucket string) error {
+	_, err := c.S3.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return fmt.Errorf("ошибка удаления бакета: %w", err)
+	}
+	return nil
+}
+
+// DeleteAllVersions — удалить под префиксом все версии и маркеры удаления, пакетами по 1000.
+// В бакете без версионирования это просто все объекты. Возвращает число удалённых версий.
+func (c *Client) DeleteAllVersions(ctx context.Context, bucket, prefix string) (int, error) {
+	total := 0
+	batch := make([]types.ObjectIdentifier, 0, 1000)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		out, err := c.S3.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: batch,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка пакетного удаления: %w", err)
+		}
+		// в режиме Quiet S3 перечисляет только ошибки
+		total += len(batch) - len(out.Errors)
+		batch = batch[:0]
+		if len(out.Errors) > 0 {
+			e := out.Errors[0]
+			return fmt.Errorf("не удалось удалить %d версий, например %s (%s): %s", len(out.Errors), aws.ToString(e.Key), aws.ToString(e.VersionId), aws.ToString(e.Message))
+		}
+		return nil
+	}
+
+	// в бакете, где версионирование ни разу не включали, версия у всех "null" — удаляем
+	// просто по ключу: не все S3-совместимые хранилища принимают VersionId=null
+	vs, err := c.GetVersioning(ctx, bucket)
+	if err != nil {
+		return 0, err
+	}
+	unversioned := vs.Status == ""
+
+	// как и в DeletePrefix, удаляем по ходу листинга: уже пройденные ключи страницы дальше не сдвигают
+	err = c.WalkVersions(ctx, bucket, prefix, func(v VersionInfo) error {
+		id := types.ObjectIdentifier{Key: aws.String(v.Key)}
+		if v.VersionID != "" && !unversioned {
+			// "null" — тоже версия: объект, записанный до включения версионирования
+			id.VersionId = aws.String(v.VersionID)
+		}
+		batch = append(batch, id)
+		if len(batch) == 1000 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return total, err
+	}
+	if err := flush(); err != nil {
+		return total, err
+	}
+	return total, nil
+}
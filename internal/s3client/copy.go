@@ -0,0 +1,237 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// больше этого CopyObject не умеет — копируем частями
+	maxSingleCopy = 5 << 30
+	copyPartSize  = 512 << 20
+	copyWorkers   = 4
+	maxCopyParts  = 10000
+)
+
+// CopyObject — серверное копирование объекта. Возвращает ETag созданной копии.
+func (c *Client) CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) (string, error) {
+	head, err := c.S3.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения метаданных источника: %w", err)
+	}
+	return c.copyFromHead(ctx, srcBucket, srcKey, dstBucket, dstKey, head)
+}
+
+// CopyVersion — скопировать версию versionID поверх того же ключа: она станет текущей,
+// а все прочие версии, включая заменённую, останутся в истории. size — из листинга версий:
+// версия не меняется, поэтому HeadObject и проверка ETag нужны только большим объектам.
+func (c *Client) CopyVersion(ctx context.Context, bucket, key, versionID string, size int64) error {
+	if size > maxSingleCopy {
+		head, err := c.S3.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket:    aws.String(bucket),
+			Key:       aws.String(key),
+			VersionId: aws.String(versionID),
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка получения метаданных версии: %w", err)
+		}
+		_, err = c.copyMultipart(ctx, bucket, key, versionID, bucket, key, head)
+		return err
+	}
+	_, err := c.S3.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(bucket),
+		Key:        aws.String(key),
+		CopySource: aws.String(copySource(bucket, key, versionID)),
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка копирования версии: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) copyFromHead(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string, head *s3.HeadObjectOutput) (string, error) {
+	size := aws.ToInt64(head.ContentLength)
+	if size > maxSingleCopy {
+		return c.copyMultipart(ctx, srcBucket, srcKey, "", dstBucket, dstKey, head)
+	}
+	out, err := c.S3.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:            aws.String(dstBucket),
+		Key:               aws.String(dstKey),
+		CopySource:        aws.String(copySource(srcBucket, srcKey, "")),
+		CopySourceIfMatch: head.ETag,
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка копирования: %w", err)
+	}
+	if out.CopyObjectResult == nil {
+		return "", nil
+	}
+	return aws.ToString(out.CopyObjectResult.ETag), nil
+}
+
+// copyMultipart — копирование больших объектов через UploadPartCopy; srcVersion "" — текущая версия
+func (c *Client) copyMultipart(ctx context.Context, srcBucket, srcKey, srcVersion, dstBucket, dstKey string, head *s3.HeadObjectOutput) (string, error) {
+	size := aws.ToInt64(head.ContentLength)
+	create, err := c.S3.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(dstBucket),
+		Key:                aws.String(dstKey),
+		ContentType:        head.ContentType,
+		ContentEncoding:    head.ContentEncoding,
+		ContentDisposition: head.ContentDisposition,
+		CacheControl:       head.CacheControl,
+		Metadata:           head.Metadata,
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка создания multipart-загрузки: %w", err)
+	}
+	uploadID := create.UploadId
+
+	abort := func() {
+		_, _ = c.S3.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(dstBucket),
+			Key:      aws.String(dstKey),
+			UploadId: uploadID,
+		})
+	}
+
+	// 512 MiB × 10000 частей — около 4.88 TiB, а объект бывает до 5 TiB: тогда части крупнее
+	partSize := max(int64(copyPartSize), (size+maxCopyParts-1)/maxCopyParts)
+	partsTotal := int32((size + partSize - 1) / partSize)
+	parts := make([]types.CompletedPart, 0, partsTotal)
+	partsCh := make(chan int32, partsTotal)
+	for n := int32(1); n <= partsTotal; n++ {
+		partsCh <- n
+	}
+	close(partsCh)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	pctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for w := 0; w < copyWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range partsCh {
+				if pctx.Err() != nil {
+					return
+				}
+				first := int64(n-1) * partSize
+				last := first + partSize - 1
+				if last >= size {
+					last = size - 1
+				}
+				out, err := c.S3.UploadPartCopy(pctx, &s3.UploadPartCopyInput{
+					Bucket:            aws.String(dstBucket),
+					Key:               aws.String(dstKey),
+					UploadId:          uploadID,
+					PartNumber:        aws.Int32(n),
+					CopySource:        aws.String(copySource(srcBucket, srcKey, srcVersion)),
+					CopySourceRange:   aws.String(fmt.Sprintf("bytes=%d-%d", first, last)),
+					CopySourceIfMatch: head.ETag,
+				})
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("ошибка копирования части %d: %w", n, err)
+					}
+					mu.Unlock()
+					cancel()
+					return
+				}
+				parts = append(parts, types.CompletedPart{PartNumber: aws.Int32(n), ETag: out.CopyPartResult.ETag})
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		abort()
+		return "", firstErr
+	}
+
+	sort.Slice(parts, func(a, b int) bool {
+		return aws.ToInt32(parts[a].PartNumber) < aws.ToInt32(parts[b].PartNumber)
+	})
+	done, err := c.S3.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(dstBucket),
+		Key:             aws.String(dstKey),
+		UploadId:        uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		abort()
+		return "", fmt.Errorf("ошибка завершения multipart-копирования: %w", err)
+	}
+	return aws.ToString(done.ETag), nil
+}
+
+// MoveObject — копирование и удаление источника, только если копия проверена
+func (c *Client) MoveObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	if srcBucket == dstBucket && srcKey == dstKey {
+		return fmt.Errorf("источник и приёмник совпадают: %s/%s", srcBucket, srcKey)
+	}
+	head, err := c.S3.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка получения метаданных источника: %w", err)
+	}
+	etag, err := c.copyFromHead(ctx, srcBucket, srcKey, dstBucket, dstKey, head)
+	if err != nil {
+		return err
+	}
+
+	check, err := c.S3.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(dstBucket),
+		Key:    aws.String(dstKey),
+	})
+	if err != nil {
+		return fmt.Errorf("копия не найдена, источник не удалён: %w", err)
+	}
+	if aws.ToInt64(check.ContentLength) != aws.ToInt64(head.ContentLength) {
+		return fmt.Errorf("размер копии не совпадает (%d != %d), источник не удалён",
+			aws.ToInt64(check.ContentLength), aws.ToInt64(head.ContentLength))
+	}
+	if etag != "" && aws.ToString(check.ETag) != etag {
+		return fmt.Errorf("ETag копии не совпадает, источник не удалён")
+	}
+
+	_, err = c.S3.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return fmt.Errorf("копия создана, но источник не удалён: %w", err)
+	}
+	return nil
+}
+
+// copySource — значение x-amz-copy-source: bucket/key с экранированием сегментов ключа,
+// с версией — ещё ?versionId=
+func copySource(bucket, key, versionID string) string {
+	segs := strings.Split(key, "/")
+	for i, s := range segs {
+		segs[i] = url.PathEscape(s)
+	}
+	src := bucket + "/" + strings.Join(segs, "/")
+	if versionID != "" {
+		src += "?versionId=" + url.QueryEscape(versionID)
+	}
+	return src
+}
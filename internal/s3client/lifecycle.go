@@ -0,0 +1,323 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// LifecycleRule — правило жизненного цикла бакета в компактном виде.
+// Теги — это и формат YAML-файла lifecycle set/get, поэтому пустые поля опускаются.
+type LifecycleRule struct {
+	ID       string            `yaml:"id,omitempty" json:"id,omitempty"`
+	Disabled bool              `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	Prefix   string            `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	Tags     map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// LargerThan / SmallerThan — фильтр по размеру объекта в байтах
+	LargerThan  int64 `yaml:"larger_than,omitempty" json:"larger_than,omitempty"`
+	SmallerThan int64 `yaml:"smaller_than,omitempty" json:"smaller_than,omitempty"`
+
+	Expire      *LifecycleExpire      `yaml:"expire,omitempty" json:"expire,omitempty"`
+	Transitions []LifecycleTransition `yaml:"transitions,omitempty" json:"transitions,omitempty"`
+	Noncurrent  *LifecycleNoncurrent  `yaml:"noncurrent,omitempty" json:"noncurrent,omitempty"`
+	// AbortIncompleteDays — прерывать multipart-загрузки старше стольких дней
+	AbortIncompleteDays int32 `yaml:"abort_incomplete_days,omitempty" json:"abort_incomplete_days,omitempty"`
+}
+
+// LifecycleExpire — когда удалять текущие версии. Date — в виде 2025-01-02;
+// DeleteMarkers — снимать маркеры удаления, под которыми не осталось версий.
+type LifecycleExpire struct {
+	Days          int32  `yaml:"days,omitempty" json:"days,omitempty"`
+	Date          string `yaml:"date,omitempty" json:"date,omitempty"`
+	DeleteMarkers bool   `yaml:"delete_markers,omitempty" json:"delete_markers,omitempty"`
+}
+
+// LifecycleTransition — перенос в другой класс хранения. Days — указатель: days: 0
+// (перенести сразу) для GLACIER и подобных законен и отличается от «не задано».
+// Для старых версий Days отсчитывается от момента, когда версия перестала быть
+// текущей, Date не бывает, а Keep — сколько самых новых старых версий не трогать.
+type LifecycleTransition struct {
+	Days         *int32 `yaml:"days,omitempty" json:"days,omitempty"`
+	Date         string `yaml:"date,omitempty" json:"date,omitempty"`
+	StorageClass string `yaml:"storage_class" json:"storage_class"`
+	Keep         int32  `yaml:"keep,omitempty" json:"keep,omitempty"`
+}
+
+// LifecycleNoncurrent — что делать со старыми (не текущими) версиями
+type LifecycleNoncurrent struct {
+	ExpireDays  int32                 `yaml:"expire_days,omitempty" json:"expire_days,omitempty"`
+	Keep        int32                 `yaml:"keep,omitempty" json:"keep,omitempty"`
+	Transitions []LifecycleTransition `yaml:"transitions,omitempty" json:"transitions,omitempty"`
+}
+
+const lifecycleDate = "2006-01-02"
+
+// ErrInvalidRule — правило не собрать ещё до запроса к S3: нет действий, кривая дата и т.п.
+var ErrInvalidRule = errors.New("некорректное правило жизненного цикла")
+
+// GetLifecycle — правила бакета; у бакета без правил — пустой список без ошибки
+func (c *Client) GetLifecycle(ctx context.Context, bucket string) ([]LifecycleRule, error) {
+	out, err := c.S3.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchLifecycleConfiguration" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка чтения правил жизненного цикла: %w", err)
+	}
+	rules := make([]LifecycleRule, 0, len(out.Rules))
+	for _, r := range out.Rules {
+		rules = append(rules, fromLifecycleRule(r))
+	}
+	return rules, nil
+}
+
+// SetLifecycle — заменить все правила бакета; пустой список — то же, что DeleteLifecycle
+func (c *Client) SetLifecycle(ctx context.Context, bucket string, rules []LifecycleRule) error {
+	if len(rules) == 0 {
+		return c.DeleteLifecycle(ctx, bucket)
+	}
+	conf := &types.BucketLifecycleConfiguration{}
+	for i, r := range rules {
+		lr, err := toLifecycleRule(r)
+		if err != nil {
+			name := r.ID
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return fmt.Errorf("%w %s: %v", ErrInvalidRule, name, err)
+		}
+		conf.Rules = append(conf.Rules, lr)
+	}
+	_, err := c.S3.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(bucket),
+		LifecycleConfiguration: conf,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка записи правил жизненного цикла: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteLifecycle(ctx context.Context, bucket string) error {
+	_, err := c.S3.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return fmt.Errorf("ошибка удаления правил жизненного цикла: %w", err)
+	}
+	return nil
+}
+
+// int32Ptr — nil для нуля: в сроках удаления, keep и т.п. ноль S3 не принимает,
+// так что он и значит «не задано» (у переходов иначе — там Days указатель)
+func int32Ptr(v int32) *int32 {
+	if v == 0 {
+		return nil
+	}
+	return aws.Int32(v)
+}
+
+func int64Ptr(v int64) *int64 {
+	if v == 0 {
+		return nil
+	}
+	return aws.Int64(v)
+}
+
+func parseLifecycleDate(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(lifecycleDate, v)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная дата %q (ожидаю 2025-01-02)", v)
+	}
+	return &t, nil
+}
+
+func formatLifecycleDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(lifecycleDate)
+}
+
+func toLifecycleRule(r LifecycleRule) (types.LifecycleRule, error) {
+	lr := types.LifecycleRule{Status: types.ExpirationStatusEnabled}
+	if r.ID != "" {
+		// без ID S3 придумает его сам
+		lr.ID = aws.String(r.ID)
+	}
+	if r.Disabled {
+		lr.Status = types.ExpirationStatusDisabled
+	}
+	if r.Expire == nil && len(r.Transitions) == 0 && r.Noncurrent == nil && r.AbortIncompleteDays == 0 {
+		return lr, fmt.Errorf("нет ни одного действия: expire, transitions, noncurrent или abort_incomplete_days")
+	}
+
+	// фильтр: одно условие задаётся как есть, несколько — через And
+	keys := make([]string, 0, len(r.Tags))
+	for k := range r.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	conds := len(keys)
+	if r.Prefix != "" {
+		conds++
+	}
+	if r.LargerThan > 0 {
+		conds++
+	}
+	if r.SmallerThan > 0 {
+		conds++
+	}
+	filter := &types.LifecycleRuleFilter{}
+	switch {
+	case conds > 1:
+		and := &types.LifecycleRuleAndOperator{
+			ObjectSizeGreaterThan: int64Ptr(r.LargerThan),
+			ObjectSizeLessThan:    int64Ptr(r.SmallerThan),
+		}
+		if r.Prefix != "" {
+			and.Prefix = aws.String(r.Prefix)
+		}
+		for _, k := range keys {
+			and.Tags = append(and.Tags, types.Tag{Key: aws.String(k), Value: aws.String(r.Tags[k])})
+		}
+		filter.And = and
+	case len(keys) == 1:
+		filter.Tag = &types.Tag{Key: aws.String(keys[0]), Value: aws.String(r.Tags[keys[0]])}
+	case r.LargerThan > 0:
+		filter.ObjectSizeGreaterThan = aws.Int64(r.LargerThan)
+	case r.SmallerThan > 0:
+		filter.ObjectSizeLessThan = aws.Int64(r.SmallerThan)
+	default:
+		// пустой префикс — правило на весь бакет
+		filter.Prefix = aws.String(r.Prefix)
+	}
+	lr.Filter = filter
+
+	if e := r.Expire; e != nil {
+		date, err := parseLifecycleDate(e.Date)
+		if err != nil {
+			return lr, fmt.Errorf("expire: %w", err)
+		}
+		lr.Expiration = &types.LifecycleExpiration{Days: int32Ptr(e.Days), Date: date}
+		if e.DeleteMarkers {
+			lr.Expiration.ExpiredObjectDeleteMarker = aws.Bool(true)
+		}
+	}
+	for _, t := range r.Transitions {
+		if t.Keep != 0 {
+			return lr, fmt.Errorf("transitions: keep бывает только у noncurrent")
+		}
+		date, err := parseLifecycleDate(t.Date)
+		if err != nil {
+			return lr, fmt.Errorf("transitions: %w", err)
+		}
+		lr.Transitions = append(lr.Transitions, types.Transition{
+			Days:         t.Days,
+			Date:         date,
+			StorageClass: types.TransitionStorageClass(t.StorageClass),
+		})
+	}
+	if n := r.Noncurrent; n != nil {
+		if n.ExpireDays != 0 || n.Keep != 0 {
+			lr.NoncurrentVersionExpiration = &types.NoncurrentVersionExpiration{
+				NoncurrentDays:          int32Ptr(n.ExpireDays),
+				NewerNoncurrentVersions: int32Ptr(n.Keep),
+			}
+		}
+		for _, t := range n.Transitions {
+			if t.Date != "" {
+				return lr, fmt.Errorf("noncurrent.transitions: у старых версий нет date, только days")
+			}
+			lr.NoncurrentVersionTransitions = append(lr.NoncurrentVersionTransitions, types.NoncurrentVersionTransition{
+				NoncurrentDays:          t.Days,
+				NewerNoncurrentVersions: int32Ptr(t.Keep),
+				StorageClass:            types.TransitionStorageClass(t.StorageClass),
+			})
+		}
+	}
+	if r.AbortIncompleteDays != 0 {
+		lr.AbortIncompleteMultipartUpload = &types.AbortIncompleteMultipartUpload{DaysAfterInitiation: aws.Int32(r.AbortIncompleteDays)}
+	}
+	return lr, nil
+}
+
+func fromLifecycleRule(lr types.LifecycleRule) LifecycleRule {
+	r := LifecycleRule{
+		ID:       aws.ToString(lr.ID),
+		Disabled: lr.Status == types.ExpirationStatusDisabled,
+		// устаревший префикс на уровне правила, без Filter
+		Prefix: aws.ToString(lr.Prefix),
+	}
+	addTag := func(t types.Tag) {
+		if r.Tags == nil {
+			r.Tags = map[string]string{}
+		}
+		r.Tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	if f := lr.Filter; f != nil {
+		if a := f.And; a != nil {
+			r.Prefix = aws.ToString(a.Prefix)
+			r.LargerThan = aws.ToInt64(a.ObjectSizeGreaterThan)
+			r.SmallerThan = aws.ToInt64(a.ObjectSizeLessThan)
+			for _, t := range a.Tags {
+				addTag(t)
+			}
+		}
+		if f.Prefix != nil {
+			r.Prefix = aws.ToString(f.Prefix)
+		}
+		if f.Tag != nil {
+			addTag(*f.Tag)
+		}
+		if f.ObjectSizeGreaterThan != nil {
+			r.LargerThan = aws.ToInt64(f.ObjectSizeGreaterThan)
+		}
+		if f.ObjectSizeLessThan != nil {
+			r.SmallerThan = aws.ToInt64(f.ObjectSizeLessThan)
+		}
+	}
+
+	if e := lr.Expiration; e != nil {
+		r.Expire = &LifecycleExpire{
+			Days:          aws.ToInt32(e.Days),
+			Date:          formatLifecycleDate(e.Date),
+			DeleteMarkers: aws.ToBool(e.ExpiredObjectDeleteMarker),
+		}
+	}
+	for _, t := range lr.Transitions {
+		r.Transitions = append(r.Transitions, LifecycleTransition{
+			Days:         t.Days,
+			Date:         formatLifecycleDate(t.Date),
+			StorageClass: string(t.StorageClass),
+		})
+	}
+	if e := lr.NoncurrentVersionExpiration; e != nil || len(lr.NoncurrentVersionTransitions) > 0 {
+		n := &LifecycleNoncurrent{}
+		if e != nil {
+			n.ExpireDays = aws.ToInt32(e.NoncurrentDays)
+			n.Keep = aws.ToInt32(e.NewerNoncurrentVersions)
+		}
+		for _, t := range lr.NoncurrentVersionTransitions {
+			n.Transitions = append(n.Transitions, LifecycleTransition{
+				Days:         t.NoncurrentDays,
+				StorageClass: string(t.StorageClass),
+				Keep:         aws.ToInt32(t.NewerNoncurrentVersions),
+			})
+		}
+		r.Noncurrent = n
+	}
+	if a := lr.AbortIncompleteMultipartUpload; a != nil {
+		r.AbortIncompleteDays = aws.ToInt32(a.DaysAfterInitiation)
+	}
+	return r
+}
@@ -0,0 +1,84 @@
+package s3client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestLifecycleRuleRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		rule LifecycleRule
+	}{
+		{"весь бакет", LifecycleRule{
+			ID:     "all",
+			Expire: &LifecycleExpire{Days: 30},
+		}},
+		{"префикс", LifecycleRule{
+			ID:       "logs",
+			Disabled: true,
+			Prefix:   "logs/",
+			Expire:   &LifecycleExpire{Date: "2025-01-02"},
+		}},
+		{"один тег", LifecycleRule{
+			Tags:   map[string]string{"env": "tmp"},
+			Expire: &LifecycleExpire{DeleteMarkers: true},
+		}},
+		{"размер", LifecycleRule{
+			LargerThan:          1 << 20,
+			AbortIncompleteDays: 7,
+		}},
+		{"переход сразу", LifecycleRule{
+			Prefix: "archive/",
+			Transitions: []LifecycleTransition{
+				{Days: aws.Int32(0), StorageClass: "GLACIER"},
+			},
+		}},
+		{"всё вместе", LifecycleRule{
+			ID:          "full",
+			Prefix:      "data/",
+			Tags:        map[string]string{"env": "tmp", "team": "qa"},
+			LargerThan:  1024,
+			SmallerThan: 1 << 30,
+			Expire:      &LifecycleExpire{Days: 365},
+			Transitions: []LifecycleTransition{
+				{Days: aws.Int32(0), StorageClass: "GLACIER_IR"},
+				{Date: "2030-06-01", StorageClass: "DEEP_ARCHIVE"},
+			},
+			Noncurrent: &LifecycleNoncurrent{
+				ExpireDays: 90,
+				Keep:       3,
+				Transitions: []LifecycleTransition{
+					{Days: aws.Int32(0), StorageClass: "GLACIER"},
+					{Days: aws.Int32(30), StorageClass: "STANDARD_IA", Keep: 2},
+				},
+			},
+			AbortIncompleteDays: 7,
+		}},
+	}
+	for _, tt := range tests {
+		lr, err := toLifecycleRule(tt.rule)
+		if err != nil {
+			t.Errorf("%s: toLifecycleRule: %v", tt.name, err)
+			continue
+		}
+		if got := fromLifecycleRule(lr); !reflect.DeepEqual(got, tt.rule) {
+			t.Errorf("%s: после круга\n  получили %+v\n  ожидалось %+v", tt.name, got, tt.rule)
+		}
+	}
+}
+
+func TestLifecycleRuleTransitionDaysZero(t *testing.T) {
+	lr, err := toLifecycleRule(LifecycleRule{
+		Transitions: []LifecycleTransition{{Days: aws.Int32(0), StorageClass: "GLACIER"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// days: 0 — «перенести сразу», его нельзя потерять как незаданное
+	if d := lr.Transitions[0].Days; d == nil || *d != 0 {
+		t.Errorf("Days = %v, ожидался указатель на 0", d)
+	}
+}
@@ -29,3 +29,48 @@ func (c *Client) ListAllKeys(ctx context.Context, bucket, prefix string) ([]stri
 	}
 	return keys, nil
 }
+
+// ListAllObjects — объекты под префиксом вместе с размером, датой и ETag
+func (c *Client) ListAllObjects(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := c.WalkObjects(ctx, bucket, prefix, func(o ObjectInfo) error {
+		objects = append(objects, o)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// WalkObjects — то же, что ListAllObjects, но без накопления: fn вызывается
+// для каждого объекта по мере получения страниц (ключи идут по возрастанию).
+// Ошибка из fn останавливает обход и возвращается как есть.
+func (c *Client) WalkObjects(ctx context.Context, bucket, prefix string, fn func(ObjectInfo) error) error {
+	p := s3.NewListObjectsV2Paginator(c.S3, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}, func(o *s3.ListObjectsV2PaginatorOptions) { o.Limit = 1000 })
+
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("ошибка листинга: %w", err)
+		}
+		for _, it := range out.Contents {
+			if it.Key == nil {
+				continue
+			}
+			err := fn(ObjectInfo{
+				Key:          aws.ToString(it.Key),
+				Size:         aws.ToInt64(it.Size),
+				LastModified: derefTime(it.LastModified),
+				ETag:         aws.ToString(it.ETag),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ type ObjectInfo struct {
 	Key          string
 	Size         int64
 	LastModified time.Time
+	ETag         string
 }
 
 func (c *Client) ListOneLevel(ctx context.Context, bucket, prefix string) ([]string, []ObjectInfo, error) {
@@ -51,6 +52,7 @@ func (c *Client) ListOneLevel(ctx context.Context, bucket, prefix string) ([]str
 				Key:          *it.Key,
 				Size:         aws.ToInt64(it.Size),
 				LastModified: derefTime(it.LastModified),
+				ETag:         aws.ToString(it.ETag),
 			})
 		}
 	}
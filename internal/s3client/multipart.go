@@ -0,0 +1,83 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// UploadInfo — незавершённая multipart-загрузка. Части такой загрузки хранятся
+// и оплачиваются, хотя объекта ещё нет и в ls его не видно.
+type UploadInfo struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+}
+
+// ListUploads — незавершённые multipart-загрузки под префиксом, от старых к новым
+func (c *Client) ListUploads(ctx context.Context, bucket, prefix string) ([]UploadInfo, error) {
+	p := s3.NewListMultipartUploadsPaginator(c.S3, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	var res []UploadInfo
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка листинга multipart-загрузок: %w", err)
+		}
+		for _, u := range out.Uploads {
+			if u.Key == nil || u.UploadId == nil {
+				continue
+			}
+			res = append(res, UploadInfo{
+				Key:       aws.ToString(u.Key),
+				UploadID:  aws.ToString(u.UploadId),
+				Initiated: derefTime(u.Initiated),
+			})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Initiated.Before(res[j].Initiated) })
+	return res, nil
+}
+
+// UploadParts — сколько частей загрузки уже на сервере и их общий размер
+func (c *Client) UploadParts(ctx context.Context, bucket, key, uploadID string) (int, int64, error) {
+	p := s3.NewListPartsPaginator(c.S3, &s3.ListPartsInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+
+	parts := 0
+	var bytes int64
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("ошибка листинга частей: %w", err)
+		}
+		for _, part := range out.Parts {
+			parts++
+			bytes += aws.ToInt64(part.Size)
+		}
+	}
+	return parts, bytes, nil
+}
+
+// AbortUpload — прервать загрузку: S3 удаляет её части, место освобождается
+func (c *Client) AbortUpload(ctx context.Context, bucket, key, uploadID string) error {
+	_, err := c.S3.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка прерывания загрузки: %w", err)
+	}
+	return nil
+}
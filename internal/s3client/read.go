@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -15,13 +16,23 @@ type ObjectStat struct {
 	LastModified string
 	ETag         string
 	ContentType  string
+	VersionID    string // пусто, если версионирование в бакете не включали
+	// ModTime и Metadata — для машинно-читаемого вывода
+	ModTime  time.Time
+	Metadata map[string]string
 }
 
 // StatObject — получить метаданные
 func (c *Client) StatObject(ctx context.Context, bucket, key string) (*ObjectStat, error) {
+	return c.StatVersion(ctx, bucket, key, "")
+}
+
+// StatVersion — метаданные версии versionID ("" — текущей)
+func (c *Client) StatVersion(ctx context.Context, bucket, key, versionID string) (*ObjectStat, error) {
 	out, err := c.S3.HeadObject(ctx, &s3.HeadObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: versionPtr(versionID),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения метаданных: %w", err)
@@ -32,14 +43,23 @@ func (c *Client) StatObject(ctx context.Context, bucket, key string) (*ObjectSta
 		LastModified: aws.ToTime(out.LastModified).Format("2025-01-02 15:20"),
 		ETag:         aws.ToString(out.ETag),
 		ContentType:  aws.ToString(out.ContentType),
+		VersionID:    aws.ToString(out.VersionId),
+		ModTime:      aws.ToTime(out.LastModified),
+		Metadata:     out.Metadata,
 	}, nil
 }
 
 // CatObject — вывести объект(stdout).
 func (c *Client) CatObject(ctx context.Context, bucket, key string, w io.Writer) error {
+	return c.CatVersion(ctx, bucket, key, "", w)
+}
+
+// CatVersion — вывести версию versionID ("" — текущую)
+func (c *Client) CatVersion(ctx context.Context, bucket, key, versionID string, w io.Writer) error {
 	out, err := c.S3.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: versionPtr(versionID),
 	})
 	if err != nil {
 		return fmt.Errorf("ошибка чтения объекта: %w", err)
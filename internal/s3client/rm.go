@@ -2,6 +2,7 @@ package s3client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -79,3 +80,94 @@ func (c *Client) DeletePrefix(ctx context.Context, bucket, prefix string) (int,
 	}
 	return total, nil
 }
+
+// DeleteVersion — удалить версию объекта навсегда (или маркер удаления — тогда объект
+// «воскреснет»). В отличие от DeleteObject не ждём исчезновения ключа: другие версии остаются.
+func (c *Client) DeleteVersion(ctx context.Context, bucket, key, versionID string) error {
+	_, err := c.S3.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: aws.String(versionID),
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка удаления версии объекта: %w", err)
+	}
+	return nil
+}
+
+// ErrPartialDelete — S3 отказал в удалении части версий; остальные удалены
+var ErrPartialDelete = errors.New("удалены не все версии")
+
+// DeleteVersions — пакетное удаление заданных версий и маркеров удаления (по 1000 за запрос).
+// Отказы по отдельным версиям не прерывают удаление: в конце — ошибка с ErrPartialDelete.
+// Ошибка самого запроса прерывает сразу.
+func (c *Client) DeleteVersions(ctx context.Context, bucket string, vs []VersionInfo) (int, error) {
+	var (
+		total, failed int
+		first         *types.Error
+	)
+	for start := 0; start < len(vs); start += 1000 {
+		end := start + 1000
+		if end > len(vs) {
+			end = len(vs)
+		}
+		batch := make([]types.ObjectIdentifier, 0, end-start)
+		for _, v := range vs[start:end] {
+			batch = append(batch, types.ObjectIdentifier{Key: aws.String(v.Key), VersionId: aws.String(v.VersionID)})
+		}
+		out, err := c.S3.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: batch,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return total, fmt.Errorf("ошибка пакетного удаления: %w", err)
+		}
+		total += len(batch) - len(out.Errors)
+		if len(out.Errors) > 0 {
+			// отказ по отдельным версиям — не повод бросать следующие пачки
+			failed += len(out.Errors)
+			if first == nil {
+				first = &out.Errors[0]
+			}
+		}
+	}
+	if first != nil {
+		return total, fmt.Errorf("%w: не удалось удалить %d, например %s (%s): %s", ErrPartialDelete, failed, aws.ToString(first.Key), aws.ToString(first.VersionId), aws.ToString(first.Message))
+	}
+	return total, nil
+}
+
+// DeleteKeys — пакетное удаление заданных ключей (по 1000 за запрос)
+func (c *Client) DeleteKeys(ctx context.Context, bucket string, keys []string) (int, error) {
+	total := 0
+	for start := 0; start < len(keys); start += 1000 {
+		end := start + 1000
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := make([]types.ObjectIdentifier, 0, end-start)
+		for _, k := range keys[start:end] {
+			batch = append(batch, types.ObjectIdentifier{Key: aws.String(k)})
+		}
+		out, err := c.S3.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: batch,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return total, fmt.Errorf("ошибка пакетного удаления: %w", err)
+		}
+		if len(out.Errors) > 0 {
+			e := out.Errors[0]
+			total += len(batch) - len(out.Errors)
+			return total, fmt.Errorf("не удалось удалить %d объектов, например %s: %s", len(out.Errors), aws.ToString(e.Key), aws.ToString(e.Message))
+		}
+		total += len(batch)
+	}
+	return total, nil
+}
@@ -0,0 +1,113 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// VersionInfo — версия объекта или маркер удаления (DeleteMarker, у него нет размера)
+type VersionInfo struct {
+	Key          string
+	VersionID    string
+	Size         int64
+	LastModified time.Time
+	ETag         string
+	IsLatest     bool
+	DeleteMarker bool
+}
+
+// WalkVersions — все версии и маркеры удаления под префиксом, по мере получения страниц.
+// Порядок как у S3: ключи по возрастанию, у одного ключа — от новой версии к старой.
+func (c *Client) WalkVersions(ctx context.Context, bucket, prefix string, fn func(VersionInfo) error) error {
+	p := s3.NewListObjectVersionsPaginator(c.S3, &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}, func(o *s3.ListObjectVersionsPaginatorOptions) { o.Limit = 1000 })
+
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("ошибка листинга версий: %w", err)
+		}
+		for _, v := range versionsPage(out) {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ListVersionsOneLevel — как ListOneLevel, но по версиям: «папки» находятся и там,
+// где остались только старые версии или маркеры удаления
+func (c *Client) ListVersionsOneLevel(ctx context.Context, bucket, prefix string) ([]string, []VersionInfo, error) {
+	p := s3.NewListObjectVersionsPaginator(c.S3, &s3.ListObjectVersionsInput{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}, func(o *s3.ListObjectVersionsPaginatorOptions) { o.Limit = 1000 })
+
+	var (
+		prefixes []string
+		versions []VersionInfo
+	)
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ошибка листинга версий: %w", err)
+		}
+		for _, cp := range out.CommonPrefixes {
+			if cp.Prefix != nil {
+				prefixes = append(prefixes, *cp.Prefix)
+			}
+		}
+		versions = append(versions, versionsPage(out)...)
+	}
+	return prefixes, versions, nil
+}
+
+// versionsPage — версии и маркеры удаления страницы одним списком.
+// S3 отдаёт их двумя массивами, поэтому сводим обратно в общий порядок.
+func versionsPage(out *s3.ListObjectVersionsOutput) []VersionInfo {
+	vs := make([]VersionInfo, 0, len(out.Versions)+len(out.DeleteMarkers))
+	for _, v := range out.Versions {
+		if v.Key == nil {
+			continue
+		}
+		vs = append(vs, VersionInfo{
+			Key:          aws.ToString(v.Key),
+			VersionID:    aws.ToString(v.VersionId),
+			Size:         aws.ToInt64(v.Size),
+			LastModified: derefTime(v.LastModified),
+			ETag:         aws.ToString(v.ETag),
+			IsLatest:     aws.ToBool(v.IsLatest),
+		})
+	}
+	for _, m := range out.DeleteMarkers {
+		if m.Key == nil {
+			continue
+		}
+		vs = append(vs, VersionInfo{
+			Key:          aws.ToString(m.Key),
+			VersionID:    aws.ToString(m.VersionId),
+			LastModified: derefTime(m.LastModified),
+			IsLatest:     aws.ToBool(m.IsLatest),
+			DeleteMarker: true,
+		})
+	}
+	sort.SliceStable(vs, func(i, j int) bool {
+		if vs[i].Key != vs[j].Key {
+			return vs[i].Key < vs[j].Key
+		}
+		if vs[i].IsLatest != vs[j].IsLatest {
+			return vs[i].IsLatest
+		}
+		return vs[i].LastModified.After(vs[j].LastModified)
+	})
+	return vs
+}
@@ -0,0 +1,57 @@
+package transfer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// createTemp — временный файл в том же каталоге, что и localPath:
+// туда идёт скачивание, на место он попадает только через commitTemp
+func createTemp(localPath string) (*os.File, error) {
+	f, err := os.CreateTemp(filepath.Dir(localPath), "."+filepath.Base(localPath)+".*.s3cli-tmp")
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать временный файл для %q: %w", localPath, err)
+	}
+	return f, nil
+}
+
+// partialPaths — фиксированные имена недокачанного файла и его состояния для --continue
+func partialPaths(localPath string) (partial, state string) {
+	dir, base := filepath.Dir(localPath), filepath.Base(localPath)
+	return filepath.Join(dir, "."+base+".s3cli-partial"), filepath.Join(dir, "."+base+stateSuffix)
+}
+
+// commitTemp — fsync, права и атомарная замена localPath временным файлом
+func commitTemp(f *os.File, localPath string) error {
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(localPath); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err := f.Chmod(mode); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("не удалось выставить права %q: %w", f.Name(), err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("ошибка fsync %q: %w", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("ошибка закрытия %q: %w", f.Name(), err)
+	}
+	if err := os.Rename(f.Name(), localPath); err != nil {
+		return fmt.Errorf("не удалось переименовать %q в %q: %w", f.Name(), localPath, err)
+	}
+	// чтобы переименование пережило сбой питания; на некоторых ОС каталоги не синхронизируются — не страшно
+	if d, err := os.Open(filepath.Dir(localPath)); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+// discardTemp — убрать временный файл после неудачи
+func discardTemp(f *os.File) {
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+}
@@ -0,0 +1,148 @@
+package transfer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ключи user-метаданных (x-amz-meta-*) с атрибутами файла. Пишем сразу в двух видах:
+// как rclone (mtime — секунды с дробной частью, mode — восьмеричный, uid, gid)
+// и как mc (mc-attrs — "mode:33188/mtime:1700000000#123/uid:0/gid:0").
+const (
+	metaMtime   = "mtime"
+	metaMode    = "mode"
+	metaUID     = "uid"
+	metaGID     = "gid"
+	metaMcAttrs = "mc-attrs"
+)
+
+// fileAttrs — то, что сохраняет и восстанавливает --preserve
+type fileAttrs struct {
+	ModTime  time.Time
+	Mode     uint32 // как st_mode: тип файла и права
+	HasMode  bool
+	UID, GID int
+	HasOwner bool
+}
+
+// attrsMeta — метаданные для загрузки файла; owner — сохранять ли uid/gid
+func attrsMeta(fi os.FileInfo, owner bool) map[string]string {
+	mt := fi.ModTime()
+	mode := unixMode(fi.Mode())
+	m := map[string]string{
+		metaMtime: fmt.Sprintf("%d.%09d", mt.Unix(), mt.Nanosecond()),
+		metaMode:  strconv.FormatUint(uint64(mode), 8),
+	}
+	mc := []string{
+		fmt.Sprintf("mode:%d", mode),
+		fmt.Sprintf("mtime:%d#%d", mt.Unix(), mt.Nanosecond()),
+	}
+	if owner {
+		if uid, gid, ok := fileOwner(fi); ok {
+			m[metaUID] = strconv.Itoa(uid)
+			m[metaGID] = strconv.Itoa(gid)
+			mc = append(mc, fmt.Sprintf("uid:%d", uid), fmt.Sprintf("gid:%d", gid))
+		}
+	}
+	m[metaMcAttrs] = strings.Join(mc, "/")
+	return m
+}
+
+// parseAttrs — атрибуты из метаданных объекта; ключи rclone важнее mc-attrs
+func parseAttrs(meta map[string]string) fileAttrs {
+	var a fileAttrs
+	mc := parseMcAttrs(meta[metaMcAttrs])
+
+	if t, ok := parseMtime(meta[metaMtime]); ok {
+		a.ModTime = t
+	} else if v, ok := mc["mtime"]; ok {
+		sec, nsec, _ := strings.Cut(v, "#")
+		if nsec == "" {
+			nsec = "0"
+		}
+		s, err1 := strconv.ParseInt(sec, 10, 64)
+		n, err2 := strconv.ParseInt(nsec, 10, 64)
+		if err1 == nil && err2 == nil {
+			a.ModTime = time.Unix(s, n)
+		}
+	}
+
+	if v, err := strconv.ParseUint(meta[metaMode], 8, 32); err == nil {
+		a.Mode, a.HasMode = uint32(v), true
+	} else if v, err := strconv.ParseUint(mc["mode"], 10, 32); err == nil {
+		a.Mode, a.HasMode = uint32(v), true
+	}
+
+	uid, gid := meta[metaUID], meta[metaGID]
+	if uid == "" || gid == "" {
+		uid, gid = mc["uid"], mc["gid"]
+	}
+	u, err1 := strconv.Atoi(uid)
+	g, err2 := strconv.Atoi(gid)
+	if err1 == nil && err2 == nil {
+		a.UID, a.GID, a.HasOwner = u, g, true
+	}
+	return a
+}
+
+// parseMtime — "1700000000.123456789" (rclone) или RFC 3339
+func parseMtime(v string) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, false
+	}
+	sec, frac, _ := strings.Cut(v, ".")
+	if s, err := strconv.ParseInt(sec, 10, 64); err == nil {
+		var n int64
+		if frac != "" {
+			frac = (frac + "000000000")[:9]
+			if n, err = strconv.ParseInt(frac, 10, 64); err != nil {
+				return time.Time{}, false
+			}
+		}
+		return time.Unix(s, n), true
+	}
+	if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func parseMcAttrs(v string) map[string]string {
+	out := make(map[string]string)
+	for _, kv := range strings.Split(v, "/") {
+		if k, val, ok := strings.Cut(kv, ":"); ok {
+			out[k] = val
+		}
+	}
+	return out
+}
+
+// applyAttrs — вернуть файлу права, время и (если owner) владельца из метаданных объекта
+func applyAttrs(path string, meta map[string]string, owner bool) error {
+	a := parseAttrs(meta)
+	if owner && a.HasOwner {
+		if err := chown(path, a.UID, a.GID); err != nil {
+			return fmt.Errorf("не удалось сменить владельца %q: %w", path, err)
+		}
+	}
+	if a.HasMode {
+		if err := os.Chmod(path, os.FileMode(a.Mode&0o777)); err != nil {
+			return fmt.Errorf("не удалось сменить права %q: %w", path, err)
+		}
+	}
+	if !a.ModTime.IsZero() {
+		if err := os.Chtimes(path, a.ModTime, a.ModTime); err != nil {
+			return fmt.Errorf("не удалось выставить время %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// unixMode — os.FileMode в st_mode (S_IFREG | права), как его пишут rclone и mc
+func unixMode(m os.FileMode) uint32 {
+	const sIFREG = 0o100000
+	return sIFREG | uint32(m.Perm())
+}
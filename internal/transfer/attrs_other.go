@@ -0,0 +1,14 @@
+//go:build !unix
+
+package transfer
+
+import "os"
+
+// владельцев в стиле POSIX здесь нет — uid/gid не сохраняем и не восстанавливаем
+func fileOwner(fi os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
+
+func chown(path string, uid, gid int) error {
+	return nil
+}
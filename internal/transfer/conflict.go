@@ -0,0 +1,173 @@
+package transfer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Conflict — что делать, если одно и то же имя нужно и как файл, и как каталог:
+// в бакете есть и "a", и "a/b", или на диске уже лежит не то
+type Conflict int
+
+const (
+	// ConflictFail — ничего не качать и перечислить конфликты (по умолчанию)
+	ConflictFail Conflict = iota
+	// ConflictSkip — не качать то, что не помещается
+	ConflictSkip
+	// ConflictSuffix — положить под свободным именем вида "a (1)"
+	ConflictSuffix
+)
+
+// resolveConflicts — найти конфликты файл/каталог до начала скачивания и разрешить их по policy.
+// markers — каталоги из ключей-маркеров вида "folder/". Возвращает, что качать, какие
+// каталоги создать и что было сделано с конфликтами.
+func resolveConflicts(root string, items []DownloadItem, markers []string, policy Conflict) ([]DownloadItem, []string, []FailedItem, error) {
+	root = filepath.Clean(root)
+	var conflicts []FailedItem
+
+	// 1. на диске файл там, где нужен каталог — страдает всё, что под ним
+	dirs := planDirs(root, items, markers)
+	for _, d := range sortedKeys(dirs) {
+		fi, err := os.Stat(d)
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		under := func(p string) bool { return p == d || strings.HasPrefix(p, d+string(filepath.Separator)) }
+		switch policy {
+		case ConflictSkip:
+			kept := items[:0]
+			for _, it := range items {
+				if under(it.Local) {
+					conflicts = append(conflicts, FailedItem{Key: it.Key, Local: it.Local,
+						Err: fmt.Errorf("%s: пропущено, %q на диске — файл, а не каталог", it.Key, d)})
+					continue
+				}
+				kept = append(kept, it)
+			}
+			items = kept
+			markers = dropUnder(markers, under)
+		case ConflictSuffix:
+			nd := freeName(d, dirs, nil, false)
+			for i := range items {
+				if under(items[i].Local) {
+					items[i].Local = nd + strings.TrimPrefix(items[i].Local, d)
+				}
+			}
+			for i := range markers {
+				if under(markers[i]) {
+					markers[i] = nd + strings.TrimPrefix(markers[i], d)
+				}
+			}
+			conflicts = append(conflicts, FailedItem{Local: d,
+				Err: fmt.Errorf("%q на диске — файл, каталог создан как %q", d, nd)})
+		default:
+			conflicts = append(conflicts, FailedItem{Local: d,
+				Err: fmt.Errorf("%q на диске — файл, а нужен каталог", d)})
+		}
+	}
+
+	// 2. объект там, где по другим ключам (или на диске) каталог
+	dirs = planDirs(root, items, markers)
+	files := make(map[string]bool, len(items))
+	for _, it := range items {
+		files[it.Local] = true
+	}
+	kept := items[:0]
+	for _, it := range items {
+		why := ""
+		if dirs[it.Local] {
+			why = "в бакете есть и объект, и «папка» с таким именем"
+		} else if fi, err := os.Stat(it.Local); err == nil && fi.IsDir() {
+			why = "на диске уже есть каталог с таким именем"
+		}
+		if why == "" {
+			kept = append(kept, it)
+			continue
+		}
+		switch policy {
+		case ConflictSkip:
+			conflicts = append(conflicts, FailedItem{Key: it.Key, Local: it.Local,
+				Err: fmt.Errorf("%s: пропущено, %s", it.Key, why)})
+		case ConflictSuffix:
+			nl := freeName(it.Local, dirs, files, true)
+			files[nl] = true
+			conflicts = append(conflicts, FailedItem{Key: it.Key, Local: nl,
+				Err: fmt.Errorf("%s: %s, сохранено как %q", it.Key, why, nl)})
+			it.Local = nl
+			kept = append(kept, it)
+		default:
+			conflicts = append(conflicts, FailedItem{Key: it.Key, Local: it.Local,
+				Err: fmt.Errorf("%s: %s", it.Key, why)})
+		}
+	}
+	items = kept
+
+	if policy == ConflictFail && len(conflicts) > 0 {
+		var b strings.Builder
+		b.WriteString("конфликты файл/каталог, ничего не скачано (см. --conflict skip|suffix):")
+		for _, c := range conflicts {
+			b.WriteString("\n  " + c.Err.Error())
+		}
+		return nil, nil, conflicts, fmt.Errorf("%s", b.String())
+	}
+	return items, markers, conflicts, nil
+}
+
+// planDirs — все каталоги внутри root, которые понадобятся: маркеры и родители файлов
+func planDirs(root string, items []DownloadItem, markers []string) map[string]bool {
+	dirs := make(map[string]bool)
+	add := func(d string) {
+		for d != root && d != "." && d != string(filepath.Separator) && !dirs[d] {
+			dirs[d] = true
+			d = filepath.Dir(d)
+		}
+	}
+	for _, m := range markers {
+		add(m)
+	}
+	for _, it := range items {
+		add(filepath.Dir(it.Local))
+	}
+	return dirs
+}
+
+// freeName — "имя (N)", не занятое ни планом, ни диском; у файлов номер встаёт перед расширением
+func freeName(p string, dirs, files map[string]bool, keepExt bool) string {
+	base, ext := p, ""
+	if keepExt {
+		ext = filepath.Ext(p)
+		base = strings.TrimSuffix(p, ext)
+	}
+	for n := 1; ; n++ {
+		c := fmt.Sprintf("%s (%d)%s", base, n, ext)
+		if dirs[c] || files[c] {
+			continue
+		}
+		if _, err := os.Lstat(c); err == nil {
+			continue
+		}
+		return c
+	}
+}
+
+func dropUnder(paths []string, under func(string) bool) []string {
+	kept := paths[:0]
+	for _, p := range paths {
+		if !under(p) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,109 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/schollz/progressbar/v3"
+	"github.com/wolfsTail/s3cli/internal/s3client"
+)
+
+type CopyStats struct {
+	TotalFiles int
+	Copied     int
+	Failed     int
+}
+
+// CopyItem — пара источник/приёмник для копирования
+type CopyItem struct {
+	SrcKey string
+	DstKey string
+}
+
+// CopyOptions — параметры копирования
+type CopyOptions struct {
+	Jobs         int
+	ShowProgress bool
+	// Move — удалять источник после проверенного копирования
+	Move bool
+	// Retry — повтор объекта целиком при временных ошибках
+	Retry RetryPolicy
+	// Limiter — ограничение скорости потокового копирования между алиасами
+	Limiter *Limiter
+}
+
+// CopyKeys — параллельное копирование (или перемещение) списка объектов.
+// Если src и dst — один клиент, копирование серверное, иначе данные идут потоком через StreamCopy.
+func CopyKeys(ctx context.Context, src, dst *s3client.Client, srcBucket, dstBucket string, items []CopyItem, opts CopyOptions) (CopyStats, error) {
+	jobsCh := make(chan CopyItem, len(items))
+	resCh := make(chan error, len(items))
+	for _, it := range items {
+		jobsCh <- it
+	}
+	close(jobsCh)
+
+	var bar *progressbar.ProgressBar
+	if opts.ShowProgress {
+		desc := "CP (files)"
+		if opts.Move {
+			desc = "MV (files)"
+		}
+		bar = progressbar.NewOptions(
+			len(items),
+			progressbar.OptionSetWriter(os.Stderr),
+			progressbar.OptionSetDescription(desc),
+			progressbar.OptionShowCount(),
+			progressbar.OptionClearOnFinish(),
+			progressbar.OptionSetWidth(20),
+		)
+	}
+
+	var wg sync.WaitGroup
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = 1
+	}
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			up := manager.NewUploader(dst.S3)
+			for j := range jobsCh {
+				err := opts.Retry.Do(ctx, func() error {
+					switch {
+					case src != dst:
+						return streamOne(ctx, src.S3, dst.S3, up, srcBucket, j.SrcKey, dstBucket, j.DstKey, opts.Move, opts.Limiter, false)
+					case opts.Move:
+						return src.MoveObject(ctx, srcBucket, j.SrcKey, dstBucket, j.DstKey)
+					default:
+						_, err := src.CopyObject(ctx, srcBucket, j.SrcKey, dstBucket, j.DstKey)
+						return err
+					}
+				})
+				if err != nil {
+					resCh <- fmt.Errorf("ошибка копирования %s -> %s: %w", j.SrcKey, j.DstKey, err)
+				} else {
+					resCh <- nil
+				}
+				if bar != nil {
+					_ = bar.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(resCh)
+
+	stats := CopyStats{TotalFiles: len(items)}
+	for err := range resCh {
+		if err != nil {
+			stats.Failed++
+		} else {
+			stats.Copied++
+		}
+	}
+	return stats, nil
+}
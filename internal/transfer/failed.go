@@ -0,0 +1,15 @@
+package transfer
+
+import "sort"
+
+// FailedItem — файл или объект, который не удалось передать, и причина.
+// Воркеры пачек шлют его и как результат по каждому элементу: Err == nil — передан.
+type FailedItem struct {
+	Local string
+	Key   string
+	Err   error
+}
+
+func sortFailed(items []FailedItem) {
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+}
@@ -2,27 +2,45 @@ package transfer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/schollz/progressbar/v3"
+	"github.com/wolfsTail/s3cli/internal/filter"
+	"github.com/wolfsTail/s3cli/internal/s3client"
 )
 
 type GetStats struct {
 	TotalFiles int
 	Downloaded int
 	Failed     int
+	// Skipped — пропущено по политике перезаписи
+	Skipped int
+	// FailedItems — что именно не скачалось
+	FailedItems []FailedItem
+	// Rejected — ключи, которые нельзя безопасно положить в каталог назначения
+	Rejected []FailedItem
+	// Conflicts — конфликты файл/каталог и как они разрешены
+	Conflicts []FailedItem
+	// Dirs — сколько каталогов создано по ключам-маркерам "folder/"
+	Dirs int
+	// Warnings — что пропущено не по ошибке (ссылки без --links=store)
+	Warnings []string
 }
 
 type progressWriterAt struct {
+	ctx context.Context
 	f   *os.File
 	bar *progressbar.ProgressBar
+	l   *Limiter
 }
 
 func (p *progressWriterAt) WriteAt(b []byte, off int64) (int, error) {
@@ -30,29 +48,88 @@ func (p *progressWriterAt) WriteAt(b []byte, off int64) (int, error) {
 	if p.bar != nil && n > 0 {
 		_ = p.bar.Add(n)
 	}
+	if werr := p.l.WaitN(p.ctx, n); werr != nil && err == nil {
+		err = werr
+	}
 	return n, err
 }
 
-func DownloadFile(ctx context.Context, s3c *s3.Client, bucket, key, localPath string, showProgress bool) error {
+// GetOptions — параметры скачивания
+type GetOptions struct {
+	Jobs         int
+	ShowProgress bool
+	// Continue — докачивать частично скачанные файлы и сохранять состояние при сбое
+	Continue bool
+	// Retry — повтор объекта целиком при временных ошибках
+	Retry RetryPolicy
+	// Limiter — общее ограничение скорости (nil — без ограничения)
+	Limiter *Limiter
+	// Overwrite — что делать с уже существующими файлами
+	Overwrite Overwrite
+	// Filter — какие объекты префикса качать (nil — все)
+	Filter *filter.Filter
+	// Sanitize — переписывать опасные ключи (.., абсолютные пути, NUL) вместо отказа
+	Sanitize bool
+	// Conflict — что делать, если имя нужно и как файл, и как каталог
+	Conflict Conflict
+	// Preserve — восстановить mtime и права из метаданных объекта, PreserveOwner — ещё uid/gid
+	Preserve      bool
+	PreserveOwner bool
+	// Links — LinksStore: создавать ссылки, сохранённые put --links=store
+	Links LinkMode
+	// VersionID — качать эту версию объекта, а не текущую (только DownloadFile)
+	VersionID string
+	// linkRoot — за пределы какого каталога не должны вести ссылки ("" — каталог самой ссылки)
+	linkRoot string
+}
+
+// version — VersionId для запросов; nil — текущая версия
+func (o GetOptions) version() *string {
+	if o.VersionID == "" {
+		return nil
+	}
+	return aws.String(o.VersionID)
+}
+
+func DownloadFile(ctx context.Context, s3c *s3.Client, bucket, key, localPath string, opts GetOptions) error {
 	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
 		return fmt.Errorf("не удалось создать каталог %q: %w", filepath.Dir(localPath), err)
 	}
-	f, err := os.Create(localPath)
-	if err != nil {
-		return fmt.Errorf("не удалось создать файл %q: %w", localPath, err)
+
+	// HeadObject нужен и для прогресса, и чтобы узнать ссылку (--links=store);
+	// без него обойдёмся, если он не обязателен
+	head, herr := s3c.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: opts.version(),
+	})
+	if herr != nil {
+		if opts.Continue || opts.Overwrite != OverwriteAlways {
+			return fmt.Errorf("ошибка получения метаданных s3://%s/%s: %w", bucket, key, herr)
+		}
+		head = nil
+	}
+	// политика перезаписи — и для ссылок тоже, как в DownloadItems: --no-clobber
+	// не должен подменять существующий файл ссылкой
+	if head != nil {
+		src := objectMeta{Size: aws.ToInt64(head.ContentLength), ModTime: aws.ToTime(head.LastModified)}
+		if skip, err := skipDownload(localPath, src, opts.Overwrite); err != nil {
+			return err
+		} else if skip {
+			return ErrSkipped
+		}
+	}
+	if target := linkTarget(head); target != "" {
+		if opts.Links != LinksStore {
+			return fmt.Errorf("%w: s3://%s/%s указывает на %q, чтобы создать её, укажите --links=store", ErrSymlink, bucket, key, target)
+		}
+		return createLink(filepath.Dir(localPath), localPath, target)
 	}
-	defer f.Close()
 
-	var pw *progressWriterAt
 	var bar *progressbar.ProgressBar
-
-	if showProgress {
-		head, herr := s3c.HeadObject(ctx, &s3.HeadObjectInput{
-			Bucket: aws.String(bucket),
-			Key:    aws.String(key),
-		})
+	if opts.ShowProgress {
 		var total int64 = -1
-		if herr == nil {
+		if head != nil {
 			total = aws.ToInt64(head.ContentLength)
 		}
 		if total > 0 {
@@ -74,50 +151,192 @@ func DownloadFile(ctx context.Context, s3c *s3.Client, bucket, key, localPath st
 				progressbar.OptionSetWidth(20),
 			)
 		}
-		pw = &progressWriterAt{f: f, bar: bar}
-	} else {
-		pw = &progressWriterAt{f: f, bar: nil}
 	}
 
-	dl := manager.NewDownloader(s3c)
+	if err := downloadOne(ctx, s3c, manager.NewDownloader(s3c), bucket, key, localPath, head, opts, bar); err != nil {
+		return fmt.Errorf("ошибка скачивания s3://%s/%s -> %q: %w", bucket, key, localPath, err)
+	}
+	return nil
+}
+
+// skipDownload — не качать ли объект src поверх существующего локального файла
+func skipDownload(localPath string, src objectMeta, ow Overwrite) (bool, error) {
+	if ow == OverwriteAlways {
+		return false, nil
+	}
+	dst, exists, err := localMeta(localPath)
+	if err != nil {
+		return false, err
+	}
+	return exists && ow.skip(src, dst), nil
+}
+
+// downloadOne — скачать объект в localPath с повторами по opts.Retry
+func downloadOne(ctx context.Context, s3c *s3.Client, dl *manager.Downloader, bucket, key, localPath string, head *s3.HeadObjectOutput, opts GetOptions, bar *progressbar.ProgressBar) error {
+	return opts.Retry.Do(ctx, func() error {
+		if bar != nil {
+			bar.Reset()
+		}
+		return downloadAttempt(ctx, s3c, dl, bucket, key, localPath, head, opts, bar)
+	})
+}
+
+// downloadAttempt — одна попытка; с Continue — диапазонами с докачкой
+func downloadAttempt(ctx context.Context, s3c *s3.Client, dl *manager.Downloader, bucket, key, localPath string, head *s3.HeadObjectOutput, opts GetOptions, bar *progressbar.ProgressBar) error {
+	if head == nil && (opts.Continue || opts.Preserve) {
+		h, err := s3c.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket:    aws.String(bucket),
+			Key:       aws.String(key),
+			VersionId: opts.version(),
+		})
+		if err != nil {
+			return err
+		}
+		head = h
+	}
+	if err := fetchObject(ctx, s3c, dl, bucket, key, localPath, head, opts, bar); err != nil {
+		return err
+	}
+	if opts.Preserve {
+		return applyAttrs(localPath, head.Metadata, opts.PreserveOwner)
+	}
+	return nil
+}
+
+// fetchObject — сами данные объекта в localPath
+func fetchObject(ctx context.Context, s3c *s3.Client, dl *manager.Downloader, bucket, key, localPath string, head *s3.HeadObjectOutput, opts GetOptions, bar *progressbar.ProgressBar) error {
+	if opts.Continue {
+		return downloadResumable(ctx, s3c, bucket, key, opts.version(), localPath, head, opts.Limiter, bar)
+	}
+
+	// качаем во временный файл рядом, на место он встаёт только целиком
+	f, err := createTemp(localPath)
+	if err != nil {
+		return err
+	}
+
+	pw := &progressWriterAt{ctx: ctx, f: f, bar: bar, l: opts.Limiter}
 	_, err = dl.Download(ctx, pw, &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: opts.version(),
 	})
 	if err != nil {
-		return fmt.Errorf("ошибка скачивания s3://%s/%s -> %q: %w", bucket, key, localPath, err)
+		discardTemp(f)
+		return err
 	}
-	return nil
+	return commitTemp(f, localPath)
+}
+
+// DownloadItem — один объект для скачивания.
+// Size и ModTime — из листинга; если ModTime пустое, а они нужны, спросим HeadObject.
+type DownloadItem struct {
+	Key     string
+	Local   string
+	Size    int64
+	ModTime time.Time
 }
 
-func DownloadKeys(ctx context.Context, s3c *s3.Client, bucket string, keys []string, prefix, localRoot string, jobs int, showProgress bool) (GetStats, error) {
+func DownloadKeys(ctx context.Context, s3c *s3.Client, bucket string, keys []string, prefix, localRoot string, opts GetOptions) (GetStats, error) {
+	objs := make([]s3client.ObjectInfo, 0, len(keys))
+	for _, k := range keys {
+		objs = append(objs, s3client.ObjectInfo{Key: k})
+	}
+	return DownloadObjects(ctx, s3c, bucket, objs, prefix, localRoot, opts)
+}
+
+// DownloadObjects — как DownloadKeys, но с размерами и датами из листинга.
+// Ключи, выходящие за пределы localRoot, не качаются и попадают в GetStats.Rejected.
+// Маркеры "folder/" становятся каталогами; конфликты файл/каталог ищутся заранее.
+func DownloadObjects(ctx context.Context, s3c *s3.Client, bucket string, objs []s3client.ObjectInfo, prefix, localRoot string, opts GetOptions) (GetStats, error) {
 	if prefix != "" && !strings.HasSuffix(prefix, "/") {
 		prefix += "/"
 	}
-	type job struct {
-		Key  string
-		Path string
+
+	items := make([]DownloadItem, 0, len(objs))
+	var (
+		rejected []FailedItem
+		markers  []string
+	)
+	for _, o := range objs {
+		rel := strings.TrimPrefix(o.Key, prefix)
+		if !opts.Filter.Match(rel) {
+			continue
+		}
+		isMarker := rel == "" || strings.HasSuffix(rel, "/")
+		if rel = strings.TrimSuffix(rel, "/"); rel == "" {
+			continue // маркер самого префикса — это localRoot
+		}
+		lp, err := localPathFor(localRoot, rel, opts.Sanitize)
+		if err != nil {
+			rejected = append(rejected, FailedItem{Key: o.Key, Err: fmt.Errorf("%q: %w", o.Key, err)})
+			continue
+		}
+		if isMarker {
+			markers = append(markers, lp)
+			continue
+		}
+		items = append(items, DownloadItem{Key: o.Key, Local: lp, Size: o.Size, ModTime: o.LastModified})
+	}
+	sortFailed(rejected)
+
+	total := len(items) + len(rejected)
+	items, markers, conflicts, err := resolveConflicts(localRoot, items, markers, opts.Conflict)
+	if err != nil {
+		return GetStats{TotalFiles: total, Rejected: rejected, Conflicts: conflicts}, err
 	}
 
-	var js []job
-	for _, k := range keys {
-		rel := strings.TrimPrefix(k, prefix)
-		rel = filepath.FromSlash(rel)
-		lp := filepath.Join(localRoot, rel)
-		js = append(js, job{Key: k, Path: lp})
+	var dirFailed []FailedItem
+	dirs := 0
+	for _, d := range markers {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			dirFailed = append(dirFailed, FailedItem{Local: d, Err: fmt.Errorf("не удалось создать каталог %q: %w", d, err)})
+			continue
+		}
+		dirs++
+	}
+
+	opts.linkRoot = localRoot
+	stats, err := DownloadItems(ctx, s3c, bucket, items, opts)
+	stats.TotalFiles = total
+	stats.Rejected = rejected
+	stats.Conflicts = conflicts
+	stats.Dirs = dirs
+	if opts.Conflict == ConflictSkip {
+		stats.Skipped += len(conflicts)
+	}
+	stats.Failed += len(dirFailed)
+	stats.FailedItems = append(stats.FailedItems, dirFailed...)
+	return stats, err
+}
+
+// LocalPathFor — путь для одиночного объекта: имя берётся из последнего сегмента ключа
+// и проверяется так же, как ключи префикса
+func LocalPathFor(dir, key string, sanitize bool) (string, error) {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		name = key[i+1:]
+	}
+	lp, err := localPathFor(dir, name, sanitize)
+	if err != nil {
+		return "", fmt.Errorf("%q: %w", key, err)
 	}
+	return lp, nil
+}
 
-	jobsCh := make(chan job, len(js))
-	resCh := make(chan error, len(js))
-	for _, j := range js {
-		jobsCh <- j
+// DownloadItems — параллельное скачивание готового списка объектов
+func DownloadItems(ctx context.Context, s3c *s3.Client, bucket string, items []DownloadItem, opts GetOptions) (GetStats, error) {
+	jobsCh := make(chan DownloadItem, len(items))
+	resCh := make(chan FailedItem, len(items))
+	for _, it := range items {
+		jobsCh <- it
 	}
 	close(jobsCh)
 
 	var bar *progressbar.ProgressBar
-	if showProgress {
+	if opts.ShowProgress {
 		bar = progressbar.NewOptions(
-			len(js),
+			len(items),
 			progressbar.OptionSetWriter(os.Stderr),
 			progressbar.OptionSetDescription("GET (files)"),
 			progressbar.OptionShowCount(),
@@ -127,41 +346,63 @@ func DownloadKeys(ctx context.Context, s3c *s3.Client, bucket string, keys []str
 	}
 
 	var wg sync.WaitGroup
+	jobs := opts.Jobs
 	if jobs <= 0 {
 		jobs = 1
 	}
+	var (
+		mu    sync.Mutex
+		links []pendingLink
+		warns []string
+	)
 	for i := 0; i < jobs; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			dl := manager.NewDownloader(s3c)
 			for j := range jobsCh {
-				if err := os.MkdirAll(filepath.Dir(j.Path), 0o755); err != nil {
-					resCh <- fmt.Errorf("не удалось создать каталог %q: %w", filepath.Dir(j.Path), err)
+				res := FailedItem{Local: j.Local, Key: j.Key}
+				if err := os.MkdirAll(filepath.Dir(j.Local), 0o755); err != nil {
+					res.Err = fmt.Errorf("не удалось создать каталог %q: %w", filepath.Dir(j.Local), err)
+					resCh <- res
 					if bar != nil {
 						_ = bar.Add(1)
 					}
 					continue
 				}
-				f, err := os.Create(j.Path)
-				if err != nil {
-					resCh <- fmt.Errorf("не удалось создать файл %q: %w", j.Path, err)
-					if bar != nil {
-						_ = bar.Add(1)
+				skip, err := skipItem(ctx, s3c, bucket, j, opts.Overwrite)
+				switch {
+				case err != nil:
+					res.Err = err
+				case skip:
+					res.Err = ErrSkipped
+				default:
+					var head *s3.HeadObjectOutput
+					if j.Size == 0 && !j.ModTime.IsZero() {
+						// пустой (по листингу) объект может оказаться ссылкой, сохранённой через --links=store
+						if h, err := s3c.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(j.Key)}); err == nil {
+							head = h
+						}
+					}
+					if target := linkTarget(head); target != "" {
+						mu.Lock()
+						if opts.Links == LinksStore {
+							// ссылки создаём в самом конце, чтобы в этом запуске никто не писал сквозь них
+							links = append(links, pendingLink{item: j, target: target})
+						} else {
+							warns = append(warns, fmt.Sprintf("%s: символическая ссылка на %q, пропущено (см. --links=store)", j.Key, target))
+						}
+						mu.Unlock()
+						if opts.Links == LinksStore {
+							continue
+						}
+						res.Err = ErrSkipped
+					} else if err := downloadOne(ctx, s3c, dl, bucket, j.Key, j.Local, head, opts, nil); err != nil {
+						// для пачек показываем бар по количеству
+						res.Err = fmt.Errorf("ошибка скачивания %s -> %q: %w", j.Key, j.Local, err)
 					}
-					continue
-				}
-				pw := &progressWriterAt{f: f, bar: nil} // для пачек показываем бар по количеству
-				_, err = dl.Download(ctx, pw, &s3.GetObjectInput{
-					Bucket: aws.String(bucket),
-					Key:    aws.String(j.Key),
-				})
-				_ = f.Close()
-				if err != nil {
-					resCh <- fmt.Errorf("ошибка скачивания %s -> %q: %w", j.Key, j.Path, err)
-				} else {
-					resCh <- nil
 				}
+				resCh <- res
 				if bar != nil {
 					_ = bar.Add(1)
 				}
@@ -169,15 +410,53 @@ func DownloadKeys(ctx context.Context, s3c *s3.Client, bucket string, keys []str
 		}()
 	}
 	wg.Wait()
+	for _, l := range links {
+		root := opts.linkRoot
+		if root == "" {
+			root = filepath.Dir(l.item.Local)
+		}
+		res := FailedItem{Local: l.item.Local, Key: l.item.Key}
+		if err := createLink(root, l.item.Local, l.target); err != nil {
+			res.Err = fmt.Errorf("%s: %w", l.item.Key, err)
+		}
+		resCh <- res
+		if bar != nil {
+			_ = bar.Add(1)
+		}
+	}
 	close(resCh)
 
-	stats := GetStats{TotalFiles: len(js)}
-	for err := range resCh {
-		if err != nil {
+	stats := GetStats{TotalFiles: len(items), Warnings: warns}
+	for r := range resCh {
+		switch {
+		case errors.Is(r.Err, ErrSkipped):
+			stats.Skipped++
+		case r.Err != nil:
 			stats.Failed++
-		} else {
+			stats.FailedItems = append(stats.FailedItems, r)
+		default:
 			stats.Downloaded++
 		}
 	}
+	sortFailed(stats.FailedItems)
 	return stats, nil
 }
+
+// skipItem — skipDownload для элемента пачки; без даты из листинга спрашиваем HeadObject
+func skipItem(ctx context.Context, s3c *s3.Client, bucket string, it DownloadItem, ow Overwrite) (bool, error) {
+	if ow == OverwriteAlways {
+		return false, nil
+	}
+	src := objectMeta{Size: it.Size, ModTime: it.ModTime}
+	if src.ModTime.IsZero() {
+		if _, exists, err := localMeta(it.Local); err != nil || !exists {
+			return false, err
+		}
+		m, ok, err := headMeta(ctx, s3c, bucket, it.Key)
+		if err != nil || !ok {
+			return false, err
+		}
+		src = m
+	}
+	return skipDownload(it.Local, src, ow)
+}
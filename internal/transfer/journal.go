@@ -0,0 +1,87 @@
+package transfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// uploadJournal — состояние незавершённой multipart-загрузки на диске
+type uploadJournal struct {
+	Bucket   string    `json:"bucket"`
+	Key      string    `json:"key"`
+	Local    string    `json:"local"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mtime"`
+	PartSize int64     `json:"part_size"`
+	// Meta — метаданные загрузки: S3 ставит их при создании, докачка их не поменяет
+	Meta     map[string]string `json:"meta,omitempty"`
+	UploadID string            `json:"upload_id"`
+	Parts    []journalPart     `json:"parts"`
+}
+
+type journalPart struct {
+	Number int32  `json:"number"`
+	ETag   string `json:"etag"`
+}
+
+func journalPath(dir, bucket, key, local string) string {
+	sum := sha256.Sum256([]byte(bucket + "\x00" + key + "\x00" + local))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func loadJournal(path string) (*uploadJournal, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("не удалось прочитать журнал %q: %w", path, err)
+	}
+	var j uploadJournal
+	if err := json.Unmarshal(b, &j); err != nil {
+		// битый журнал — просто начинаем заново
+		_ = os.Remove(path)
+		return nil, nil
+	}
+	return &j, nil
+}
+
+func saveJournal(path string, j *uploadJournal) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("не удалось создать каталог %q: %w", filepath.Dir(path), err)
+	}
+	b, err := json.Marshal(j)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации журнала: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("не удалось записать %q: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("не удалось заменить %q: %w", path, err)
+	}
+	return nil
+}
+
+// matches — журнал относится к тому же файлу в том же состоянии и с теми же метаданными
+func (j *uploadJournal) matches(bucket, key, local string, fi os.FileInfo, partSize int64, meta map[string]string) bool {
+	return j.Bucket == bucket && j.Key == key && j.Local == local &&
+		j.Size == fi.Size() && j.ModTime.Equal(fi.ModTime()) && j.PartSize == partSize &&
+		maps.Equal(j.Meta, meta) && j.UploadID != ""
+}
+
+func (j *uploadJournal) etag(n int32) string {
+	for _, p := range j.Parts {
+		if p.Number == n {
+			return p.ETag
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// metaSymlink — ключ user-метаданных с целью ссылки (--links=store)
+const metaSymlink = "s3cli-symlink"
+
+// ErrSymlink — объект хранит символическую ссылку, а создавать ссылки не просили
+var ErrSymlink = errors.New("объект — символическая ссылка")
+
+// uploadLink — пустой объект, цель ссылки — в метаданных
+func uploadLink(ctx context.Context, s3c *s3.Client, bucket, key, target string) error {
+	_, err := s3c.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Body:     bytes.NewReader(nil),
+		Metadata: map[string]string{metaSymlink: target},
+	})
+	return err
+}
+
+type pendingLink struct {
+	item   DownloadItem
+	target string
+}
+
+// linkTarget — цель ссылки, если объект сохранён через --links=store
+func linkTarget(head *s3.HeadObjectOutput) string {
+	if head == nil {
+		return ""
+	}
+	return head.Metadata[metaSymlink]
+}
+
+// createLink — создать ссылку localPath -> target. Цель должна оставаться внутри root:
+// иначе следующая запись в каталог по этой ссылке ушла бы за его пределы.
+// Пути сравниваем такими, какими их увидит ОС, — с уже существующими ссылками:
+// a/b/l -> ../.. и m -> a/b/l/.. на бумаге внутри root, а на диске m ведёт выше него.
+func createLink(root, localPath, target string) error {
+	if filepath.IsAbs(target) {
+		return fmt.Errorf("%w: ссылка %q указывает на абсолютный путь %q", ErrUnsafeKey, localPath, target)
+	}
+	outside := fmt.Errorf("%w: ссылка %q указывает за пределы %q", ErrUnsafeKey, localPath, root)
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return fmt.Errorf("не удалось создать ссылку %q: %w", localPath, err)
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Dir(localPath))
+	if err != nil {
+		return fmt.Errorf("не удалось создать ссылку %q: %w", localPath, err)
+	}
+	if !within(dir, realRoot) {
+		return outside
+	}
+	resolved, ok, err := realPath(dir, target)
+	if err != nil {
+		return fmt.Errorf("не удалось создать ссылку %q: %w", localPath, err)
+	}
+	if !ok || !within(resolved, realRoot) {
+		return outside
+	}
+
+	// создаём рядом и переименовываем — так же атомарно, как обычные файлы
+	tmp := filepath.Join(filepath.Dir(localPath), "."+filepath.Base(localPath)+".s3cli-link")
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("не удалось создать ссылку %q: %w", localPath, err)
+	}
+	if err := os.Rename(tmp, localPath); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("не удалось создать ссылку %q: %w", localPath, err)
+	}
+	return nil
+}
+
+// realPath — куда на диске ведёт target относительно реального каталога dir:
+// ссылки раскрываются по одному сегменту, до того как применяется следующий "..".
+// Несуществующий хвост присоединяется как есть; ".." в нём не проверить (там позже
+// может появиться ссылка), и тогда ok = false.
+func realPath(dir, target string) (string, bool, error) {
+	cur := dir
+	parts := strings.Split(filepath.ToSlash(target), "/")
+	for i, p := range parts {
+		switch p {
+		case "", ".":
+			continue
+		case "..":
+			cur = filepath.Dir(cur)
+			continue
+		}
+		next := filepath.Join(cur, p)
+		r, err := filepath.EvalSymlinks(next)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", false, err
+			}
+			for _, rest := range parts[i+1:] {
+				if rest == ".." {
+					return "", false, nil
+				}
+			}
+			return filepath.Join(append([]string{next}, parts[i+1:]...)...), true, nil
+		}
+		cur = r
+	}
+	return cur, true, nil
+}
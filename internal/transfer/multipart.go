@@ -0,0 +1,275 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/schollz/progressbar/v3"
+)
+
+const (
+	defaultPartSize = 8 << 20
+	maxParts        = 10000
+	partWorkers     = 4
+)
+
+// partBufs — буферы частей uploadResumable: переиспользуются между частями
+// и файлами, а не выделяются заново каждым потоком каждой загрузки
+var partBufs sync.Pool
+
+// getPartBuf — буфер длины n из пула (или новый, если в пуле нет подходящего)
+func getPartBuf(n int64) []byte {
+	if b, ok := partBufs.Get().(*[]byte); ok && int64(cap(*b)) >= n {
+		return (*b)[:n]
+	}
+	return make([]byte, n)
+}
+
+func putPartBuf(b []byte) {
+	partBufs.Put(&b)
+}
+
+// choosePartSize — размер части, чтобы уложиться в лимит 10000 частей
+func choosePartSize(size int64) int64 {
+	ps := int64(defaultPartSize)
+	if size/ps >= maxParts {
+		ps = (size + maxParts - 1) / maxParts
+		ps = (ps + (1<<20 - 1)) &^ (1<<20 - 1)
+	}
+	return ps
+}
+
+// uploadResumable — multipart-загрузка с журналом: при повторном запуске
+// докачиваются только недостающие части. meta ставится при создании загрузки.
+func uploadResumable(ctx context.Context, s3c *s3.Client, bucket, key, localPath string, f *os.File, fi os.FileInfo, meta map[string]string, journalDir string, lim *Limiter, bar *progressbar.ProgressBar) error {
+	size := fi.Size()
+	partSize := choosePartSize(size)
+	partsTotal := int32((size + partSize - 1) / partSize)
+
+	abs, err := filepath.Abs(localPath)
+	if err != nil {
+		abs = localPath
+	}
+	jpath := journalPath(journalDir, bucket, key, abs)
+	j, err := loadJournal(jpath)
+	if err != nil {
+		return err
+	}
+
+	done := make(map[int32]types.CompletedPart)
+	if j != nil && !j.matches(bucket, key, abs, fi, partSize, meta) {
+		// файл или метаданные (-p, --preserve-owner) изменились — старая загрузка больше не нужна
+		abortUpload(ctx, s3c, j)
+		_ = os.Remove(jpath)
+		j = nil
+	}
+	if j != nil {
+		uploaded, err := listUploadedParts(ctx, s3c, bucket, key, j.UploadID)
+		switch {
+		case err == nil:
+			for _, p := range uploaded {
+				n := aws.ToInt32(p.PartNumber)
+				if n < 1 || n > partsTotal || aws.ToInt64(p.Size) != partLen(n, partSize, size) {
+					continue
+				}
+				if e := j.etag(n); e != "" && e != aws.ToString(p.ETag) {
+					continue
+				}
+				done[n] = types.CompletedPart{PartNumber: aws.Int32(n), ETag: p.ETag}
+			}
+		case isNoSuchUpload(err):
+			_ = os.Remove(jpath)
+			j = nil
+		default:
+			return fmt.Errorf("ошибка получения списка частей: %w", err)
+		}
+	}
+
+	if j == nil {
+		out, err := s3c.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			Metadata: meta,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка создания multipart-загрузки: %w", err)
+		}
+		j = &uploadJournal{
+			Bucket:   bucket,
+			Key:      key,
+			Local:    abs,
+			Size:     size,
+			ModTime:  fi.ModTime(),
+			PartSize: partSize,
+			Meta:     meta,
+			UploadID: aws.ToString(out.UploadId),
+		}
+		if err := saveJournal(jpath, j); err != nil {
+			return err
+		}
+	}
+	j.Parts = j.Parts[:0]
+	for n, p := range done {
+		j.Parts = append(j.Parts, journalPart{Number: n, ETag: aws.ToString(p.ETag)})
+		if bar != nil {
+			_ = bar.Add64(partLen(n, partSize, size))
+		}
+	}
+
+	partsCh := make(chan int32, partsTotal)
+	for n := int32(1); n <= partsTotal; n++ {
+		if _, ok := done[n]; !ok {
+			partsCh <- n
+		}
+	}
+	close(partsCh)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for w := 0; w < partWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range partsCh {
+				if ctx.Err() != nil {
+					return
+				}
+				l := partLen(n, partSize, size)
+				b := getPartBuf(l)
+				part := limitReader(ctx, io.NewSectionReader(f, int64(n-1)*partSize, l), lim)
+				if _, err := io.ReadFull(part, b); err != nil {
+					putPartBuf(b)
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("ошибка чтения %q: %w", localPath, err)
+					}
+					mu.Unlock()
+					cancel()
+					return
+				}
+				out, err := s3c.UploadPart(ctx, &s3.UploadPartInput{
+					Bucket:     aws.String(bucket),
+					Key:        aws.String(key),
+					UploadId:   aws.String(j.UploadID),
+					PartNumber: aws.Int32(n),
+					Body:       bytes.NewReader(b),
+				})
+				putPartBuf(b)
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("ошибка загрузки части %d: %w", n, err)
+					}
+					mu.Unlock()
+					cancel()
+					return
+				}
+				done[n] = types.CompletedPart{PartNumber: aws.Int32(n), ETag: out.ETag}
+				j.Parts = append(j.Parts, journalPart{Number: n, ETag: aws.ToString(out.ETag)})
+				_ = saveJournal(jpath, j)
+				mu.Unlock()
+				if bar != nil {
+					_ = bar.Add64(l)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		// журнал оставляем — следующий запуск продолжит с этого места
+		return firstErr
+	}
+
+	completed := make([]types.CompletedPart, 0, len(done))
+	for _, p := range done {
+		completed = append(completed, p)
+	}
+	sort.Slice(completed, func(a, b int) bool {
+		return aws.ToInt32(completed[a].PartNumber) < aws.ToInt32(completed[b].PartNumber)
+	})
+	_, err = s3c.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(j.UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка завершения multipart-загрузки: %w", err)
+	}
+	_ = os.Remove(jpath)
+	return nil
+}
+
+// discardJournal — забыть о незавершённой загрузке (--no-resume)
+func discardJournal(ctx context.Context, s3c *s3.Client, bucket, key, localPath, journalDir string) {
+	abs, err := filepath.Abs(localPath)
+	if err != nil {
+		abs = localPath
+	}
+	jpath := journalPath(journalDir, bucket, key, abs)
+	j, _ := loadJournal(jpath)
+	if j == nil {
+		return
+	}
+	abortUpload(ctx, s3c, j)
+	_ = os.Remove(jpath)
+}
+
+func abortUpload(ctx context.Context, s3c *s3.Client, j *uploadJournal) {
+	_, _ = s3c.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(j.Bucket),
+		Key:      aws.String(j.Key),
+		UploadId: aws.String(j.UploadID),
+	})
+}
+
+func listUploadedParts(ctx context.Context, s3c *s3.Client, bucket, key, uploadID string) ([]types.Part, error) {
+	p := s3.NewListPartsPaginator(s3c, &s3.ListPartsInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	var parts []types.Part
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, out.Parts...)
+	}
+	return parts, nil
+}
+
+func isNoSuchUpload(err error) bool {
+	var nsu *types.NoSuchUpload
+	if errors.As(err, &nsu) {
+		return true
+	}
+	var re *smithyhttp.ResponseError
+	return errors.As(err, &re) && re.HTTPStatusCode() == 404
+}
+
+func partLen(n int32, partSize, size int64) int64 {
+	off := int64(n-1) * partSize
+	if off+partSize > size {
+		return size - off
+	}
+	return partSize
+}
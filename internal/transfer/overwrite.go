@@ -0,0 +1,101 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// Overwrite — что делать, если на приёмнике уже есть файл или объект
+type Overwrite int
+
+const (
+	// OverwriteAlways — перезаписывать всегда (по умолчанию)
+	OverwriteAlways Overwrite = iota
+	// OverwriteNever — не трогать существующие (--no-clobber)
+	OverwriteNever
+	// OverwriteNewer — только если источник новее приёмника (--update)
+	OverwriteNewer
+	// OverwriteSizeDiffers — пропускать, если размер совпадает (--skip-existing-same-size)
+	OverwriteSizeDiffers
+)
+
+// ErrSkipped — передача не нужна по политике перезаписи
+var ErrSkipped = errors.New("уже существует, пропущено")
+
+// objectMeta — размер и время изменения приёмника
+type objectMeta struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// skip — пропустить ли передачу src поверх существующего dst
+func (o Overwrite) skip(src, dst objectMeta) bool {
+	switch o {
+	case OverwriteNever:
+		return true
+	case OverwriteNewer:
+		return !src.ModTime.After(dst.ModTime)
+	case OverwriteSizeDiffers:
+		return src.Size == dst.Size
+	}
+	return false
+}
+
+// headMeta — метаданные объекта; ok=false, если объекта нет
+func headMeta(ctx context.Context, s3c *s3.Client, bucket, key string) (objectMeta, bool, error) {
+	out, err := s3c.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return objectMeta{}, false, nil
+		}
+		return objectMeta{}, false, fmt.Errorf("ошибка получения метаданных s3://%s/%s: %w", bucket, key, err)
+	}
+	return objectMeta{Size: aws.ToInt64(out.ContentLength), ModTime: aws.ToTime(out.LastModified)}, true, nil
+}
+
+// listMeta — метаданные всех объектов под префиксом одним листингом
+func listMeta(ctx context.Context, s3c *s3.Client, bucket, prefix string) (map[string]objectMeta, error) {
+	p := s3.NewListObjectsV2Paginator(s3c, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}, func(o *s3.ListObjectsV2PaginatorOptions) { o.Limit = 1000 })
+
+	out := make(map[string]objectMeta)
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка листинга: %w", err)
+		}
+		for _, it := range page.Contents {
+			out[aws.ToString(it.Key)] = objectMeta{Size: aws.ToInt64(it.Size), ModTime: aws.ToTime(it.LastModified)}
+		}
+	}
+	return out, nil
+}
+
+// localMeta — метаданные локального файла; ok=false, если файла нет
+func localMeta(path string) (objectMeta, bool, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return objectMeta{}, false, nil
+		}
+		return objectMeta{}, false, fmt.Errorf("не удалось получить информацию о %q: %w", path, err)
+	}
+	return objectMeta{Size: fi.Size(), ModTime: fi.ModTime()}, true, nil
+}
+
+func isNotFound(err error) bool {
+	var re *smithyhttp.ResponseError
+	return errors.As(err, &re) && re.HTTPStatusCode() == 404
+}
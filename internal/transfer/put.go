@@ -2,6 +2,7 @@ package transfer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -13,25 +14,68 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/schollz/progressbar/v3"
+	"github.com/wolfsTail/s3cli/internal/filter"
 )
 
 type PutStats struct {
 	TotalFiles int
 	Uploaded   int
 	Failed     int
+	// Skipped — пропущено по политике перезаписи
+	Skipped int
+	// FailedItems — что именно не загрузилось
+	FailedItems []FailedItem
+	// Warnings — что пропущено при обходе каталога (ссылки на каталоги, циклы, fifo)
+	Warnings []string
 }
 
-func UploadFile(ctx context.Context, s3c *s3.Client, bucket, key, localPath string, showProgress bool) error {
+// PutOptions — параметры загрузки
+type PutOptions struct {
+	Jobs         int
+	ShowProgress bool
+	// Resume — докачивать большие файлы по журналу в JournalDir
+	Resume     bool
+	JournalDir string
+	// Retry — повтор файла целиком при временных ошибках
+	Retry RetryPolicy
+	// Limiter — общее ограничение скорости (nil — без ограничения)
+	Limiter *Limiter
+	// Overwrite — что делать с уже существующими объектами
+	Overwrite Overwrite
+	// Filter — какие файлы каталога грузить (nil — все)
+	Filter *filter.Filter
+	// PartSize — размер части для потока неизвестной длины (0 — DefaultStreamPartSize)
+	PartSize int64
+	// Preserve — сохранить mtime и права файла в метаданных объекта, PreserveOwner — ещё uid/gid
+	Preserve      bool
+	PreserveOwner bool
+	// Links — что делать с символическими ссылками в каталоге
+	Links LinkMode
+}
+
+// DefaultStreamPartSize — часть для stdin: 16 МиБ × 10000 частей ≈ 156 ГиБ на объект
+const DefaultStreamPartSize = 16 << 20
+
+func UploadFile(ctx context.Context, s3c *s3.Client, bucket, key, localPath string, opts PutOptions) error {
 	f, err := os.Open(localPath)
 	if err != nil {
 		return fmt.Errorf("не удалось открыть файл %q: %w", localPath, err)
 	}
 	defer f.Close()
 
-	var body io.Reader = f
-	if showProgress {
-		fi, _ := f.Stat()
-		bar := progressbar.NewOptions64(
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("не удалось получить информацию о %q: %w", localPath, err)
+	}
+	if skip, err := skipUpload(ctx, s3c, bucket, key, fi, opts.Overwrite, nil); err != nil {
+		return err
+	} else if skip {
+		return ErrSkipped
+	}
+
+	var bar *progressbar.ProgressBar
+	if opts.ShowProgress {
+		bar = progressbar.NewOptions64(
 			fi.Size(),
 			progressbar.OptionSetDescription(fmt.Sprintf("PUT %s", filepath.Base(localPath))),
 			progressbar.OptionSetWriter(os.Stderr),
@@ -40,62 +84,170 @@ func UploadFile(ctx context.Context, s3c *s3.Client, bucket, key, localPath stri
 			progressbar.OptionClearOnFinish(),
 			progressbar.OptionSetWidth(20),
 		)
-		body = io.TeeReader(f, bar)
 	}
 
-	up := manager.NewUploader(s3c)
-	_, err = up.Upload(ctx, &s3.PutObjectInput{
+	if err := uploadOne(ctx, s3c, manager.NewUploader(s3c), bucket, key, localPath, f, fi, opts, bar); err != nil {
+		return fmt.Errorf("ошибка загрузки %q -> s3://%s/%s: %w", localPath, bucket, key, err)
+	}
+	return nil
+}
+
+// UploadStream — загрузка потока неизвестной длины (stdin) частями через manager.Uploader.
+// Поток нельзя перемотать, поэтому повтора целиком нет — повторяются только запросы частей.
+func UploadStream(ctx context.Context, s3c *s3.Client, bucket, key string, r io.Reader, opts PutOptions) error {
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = DefaultStreamPartSize
+	}
+	if opts.Overwrite == OverwriteNever {
+		if _, exists, err := headMeta(ctx, s3c, bucket, key); err != nil {
+			return err
+		} else if exists {
+			return ErrSkipped
+		}
+	}
+
+	body := limitReader(ctx, r, opts.Limiter)
+	if opts.ShowProgress {
+		bar := progressbar.NewOptions64(
+			-1,
+			progressbar.OptionSetDescription("PUT stdin"),
+			progressbar.OptionSetWriter(os.Stderr),
+			progressbar.OptionShowBytes(true),
+			progressbar.OptionShowCount(),
+			progressbar.OptionThrottle(100e6),
+			progressbar.OptionClearOnFinish(),
+			progressbar.OptionSetWidth(20),
+		)
+		body = io.TeeReader(body, bar)
+	}
+
+	up := manager.NewUploader(s3c, func(u *manager.Uploader) {
+		u.PartSize = partSize
+	})
+	if _, err := up.Upload(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 		Body:   body,
-	})
-	if err != nil {
-		return fmt.Errorf("ошибка загрузки %q -> s3://%s/%s: %w", localPath, bucket, key, err)
+	}); err != nil {
+		return fmt.Errorf("ошибка загрузки stdin -> s3://%s/%s: %w", bucket, key, err)
 	}
 	return nil
 }
 
-func UploadTree(ctx context.Context, s3c *s3.Client, bucket, prefix, localDir string, jobs int, showProgress bool) (PutStats, error) {
-	if !strings.HasSuffix(prefix, "/") {
-		prefix += "/"
+// skipUpload — не грузить ли файл поверх существующего объекта; remote — готовый листинг
+// приёмника (nil — спросить HeadObject)
+func skipUpload(ctx context.Context, s3c *s3.Client, bucket, key string, fi os.FileInfo, ow Overwrite, remote map[string]objectMeta) (bool, error) {
+	if ow == OverwriteAlways {
+		return false, nil
 	}
-	type job struct {
-		Local string
-		Key   string
+	var (
+		dst    objectMeta
+		exists bool
+	)
+	if remote != nil {
+		dst, exists = remote[key]
+	} else {
+		var err error
+		if dst, exists, err = headMeta(ctx, s3c, bucket, key); err != nil {
+			return false, err
+		}
 	}
+	return exists && ow.skip(objectMeta{Size: fi.Size(), ModTime: fi.ModTime()}, dst), nil
+}
 
-	var files []string
-	err := filepath.WalkDir(localDir, func(p string, d os.DirEntry, err error) error {
-		if err != nil {
+// uploadOne — загрузка открытого файла с повторами по opts.Retry
+func uploadOne(ctx context.Context, s3c *s3.Client, up *manager.Uploader, bucket, key, localPath string, f *os.File, fi os.FileInfo, opts PutOptions, bar *progressbar.ProgressBar) error {
+	return opts.Retry.Do(ctx, func() error {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
 			return err
 		}
-		if d.IsDir() {
-			return nil
+		if bar != nil {
+			bar.Reset()
 		}
-		files = append(files, p)
-		return nil
+		return uploadAttempt(ctx, s3c, up, bucket, key, localPath, f, fi, opts, bar)
 	})
+}
+
+// uploadAttempt — одна попытка: большие файлы идут через журнал, остальные через manager.Uploader
+func uploadAttempt(ctx context.Context, s3c *s3.Client, up *manager.Uploader, bucket, key, localPath string, f *os.File, fi os.FileInfo, opts PutOptions, bar *progressbar.ProgressBar) error {
+	var meta map[string]string
+	if opts.Preserve {
+		meta = attrsMeta(fi, opts.PreserveOwner)
+	}
+	if opts.JournalDir != "" {
+		if opts.Resume && fi.Size() > defaultPartSize {
+			return uploadResumable(ctx, s3c, bucket, key, localPath, f, fi, meta, opts.JournalDir, opts.Limiter, bar)
+		}
+		if !opts.Resume {
+			discardJournal(ctx, s3c, bucket, key, localPath, opts.JournalDir)
+		}
+	}
+
+	body := limitReader(ctx, f, opts.Limiter)
+	if bar != nil {
+		body = io.TeeReader(body, bar)
+	}
+	_, err := up.Upload(ctx, &s3.PutObjectInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Body:     body,
+		Metadata: meta,
+	})
+	return err
+}
+
+// UploadItem — один файл для загрузки. Link — цель ссылки для --links=store:
+// тогда грузится пустой объект с целью в метаданных.
+type UploadItem struct {
+	Local string
+	Key   string
+	Link  string
+}
+
+func UploadTree(ctx context.Context, s3c *s3.Client, bucket, prefix, localDir string, opts PutOptions) (PutStats, error) {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	items, warns, err := walkTree(localDir, opts.Links, opts.Filter)
 	if err != nil {
 		return PutStats{}, fmt.Errorf("ошибка обхода каталога %q: %w", localDir, err)
 	}
+	for i := range items {
+		items[i].Key = prefix + items[i].Key
+	}
 
-	rootAbs, _ := filepath.Abs(localDir)
-	jobsCh := make(chan job, len(files))
-	resCh := make(chan error, len(files))
+	// для сравнения с приёмником хватит одного листинга вместо HeadObject на каждый файл
+	var remote map[string]objectMeta
+	if opts.Overwrite != OverwriteAlways {
+		remote, err = listMeta(ctx, s3c, bucket, prefix)
+		if err != nil {
+			return PutStats{}, err
+		}
+	}
+	stats, err := uploadFiles(ctx, s3c, bucket, items, opts, remote)
+	stats.Warnings = warns
+	return stats, err
+}
 
-	for _, fpath := range files {
-		abs, _ := filepath.Abs(fpath)
-		rel, _ := filepath.Rel(rootAbs, abs)
-		rel = filepath.ToSlash(rel)
-		key := prefix + rel
-		jobsCh <- job{Local: fpath, Key: key}
+// UploadFiles — параллельная загрузка готового списка файлов
+func UploadFiles(ctx context.Context, s3c *s3.Client, bucket string, items []UploadItem, opts PutOptions) (PutStats, error) {
+	return uploadFiles(ctx, s3c, bucket, items, opts, nil)
+}
+
+func uploadFiles(ctx context.Context, s3c *s3.Client, bucket string, items []UploadItem, opts PutOptions, remote map[string]objectMeta) (PutStats, error) {
+	jobsCh := make(chan UploadItem, len(items))
+	resCh := make(chan FailedItem, len(items))
+	for _, it := range items {
+		jobsCh <- it
 	}
 	close(jobsCh)
 
 	var bar *progressbar.ProgressBar
-	if showProgress {
+	if opts.ShowProgress {
 		bar = progressbar.NewOptions(
-			len(files),
+			len(items),
 			progressbar.OptionSetWriter(os.Stderr),
 			progressbar.OptionSetDescription("PUT (files)"),
 			progressbar.OptionShowCount(),
@@ -105,6 +257,7 @@ func UploadTree(ctx context.Context, s3c *s3.Client, bucket, prefix, localDir st
 	}
 
 	var wg sync.WaitGroup
+	jobs := opts.Jobs
 	if jobs <= 0 {
 		jobs = 1
 	}
@@ -114,25 +267,9 @@ func UploadTree(ctx context.Context, s3c *s3.Client, bucket, prefix, localDir st
 			defer wg.Done()
 			up := manager.NewUploader(s3c)
 			for j := range jobsCh {
-				f, err := os.Open(j.Local)
-				if err != nil {
-					resCh <- fmt.Errorf("не удалось открыть %q: %w", j.Local, err)
-					if bar != nil {
-						_ = bar.Add(1)
-					}
-					continue
-				}
-				_, err = up.Upload(ctx, &s3.PutObjectInput{
-					Bucket: aws.String(bucket),
-					Key:    aws.String(j.Key),
-					Body:   io.Reader(f),
-				})
-				_ = f.Close()
-				if err != nil {
-					resCh <- fmt.Errorf("ошибка загрузки %q -> %s: %w", j.Local, j.Key, err)
-				} else {
-					resCh <- nil
-				}
+				res := FailedItem{Local: j.Local, Key: j.Key}
+				res.Err = uploadItem(ctx, s3c, up, bucket, j, opts, remote)
+				resCh <- res
 				if bar != nil {
 					_ = bar.Add(1)
 				}
@@ -142,13 +279,59 @@ func UploadTree(ctx context.Context, s3c *s3.Client, bucket, prefix, localDir st
 	wg.Wait()
 	close(resCh)
 
-	stats := PutStats{TotalFiles: len(files)}
-	for err := range resCh {
-		if err != nil {
+	stats := PutStats{TotalFiles: len(items)}
+	for r := range resCh {
+		switch {
+		case errors.Is(r.Err, ErrSkipped):
+			stats.Skipped++
+		case r.Err != nil:
 			stats.Failed++
-		} else {
+			stats.FailedItems = append(stats.FailedItems, r)
+		default:
 			stats.Uploaded++
 		}
 	}
+	sortFailed(stats.FailedItems)
 	return stats, nil
 }
+
+// uploadItem — один элемент пачки: ErrSkipped, если не нужен по политике перезаписи
+func uploadItem(ctx context.Context, s3c *s3.Client, up *manager.Uploader, bucket string, j UploadItem, opts PutOptions, remote map[string]objectMeta) error {
+	if j.Link != "" {
+		fi, err := os.Lstat(j.Local)
+		if err != nil {
+			return fmt.Errorf("не удалось получить информацию о %q: %w", j.Local, err)
+		}
+		if skip, err := skipUpload(ctx, s3c, bucket, j.Key, fi, opts.Overwrite, remote); err != nil || skip {
+			if skip {
+				return ErrSkipped
+			}
+			return err
+		}
+		if err := opts.Retry.Do(ctx, func() error { return uploadLink(ctx, s3c, bucket, j.Key, j.Link) }); err != nil {
+			return fmt.Errorf("ошибка загрузки ссылки %q -> %s: %w", j.Local, j.Key, err)
+		}
+		return nil
+	}
+
+	f, err := os.Open(j.Local)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть %q: %w", j.Local, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("не удалось получить информацию о %q: %w", j.Local, err)
+	}
+	skip, err := skipUpload(ctx, s3c, bucket, j.Key, fi, opts.Overwrite, remote)
+	if err != nil {
+		return err
+	}
+	if skip {
+		return ErrSkipped
+	}
+	if err := uploadOne(ctx, s3c, up, bucket, j.Key, j.Local, f, fi, opts, nil); err != nil {
+		return fmt.Errorf("ошибка загрузки %q -> %s: %w", j.Local, j.Key, err)
+	}
+	return nil
+}
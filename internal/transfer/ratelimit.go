@@ -0,0 +1,89 @@
+package transfer
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limiter — общий token bucket на все потоки и части передачи.
+// nil означает «без ограничения».
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // байт в секунду
+	tokens float64
+	last   time.Time
+}
+
+// чтобы не копить долг большими кусками, читаем не больше этого за раз
+const limitChunk = 64 << 10
+
+func NewLimiter(bytesPerSec int64) *Limiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return &Limiter{
+		rate:   float64(bytesPerSec),
+		tokens: float64(bytesPerSec),
+		last:   time.Now(),
+	}
+}
+
+// WaitN — списать n байт и подождать, если бюджет исчерпан.
+// Бюджет может уйти в минус: следующий поток ждёт дольше, так скорость делится между всеми.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *Limiter
+}
+
+// limitReader — обернуть r лимитером; без лимитера возвращает r как есть
+func limitReader(ctx context.Context, r io.Reader, l *Limiter) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, l: l}
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > limitChunk {
+		p = p[:limitChunk]
+	}
+	n, err := lr.r.Read(p)
+	if werr := lr.l.WaitN(lr.ctx, n); werr != nil && err == nil {
+		err = werr
+	}
+	return n, err
+}
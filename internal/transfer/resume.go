@@ -0,0 +1,176 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/schollz/progressbar/v3"
+)
+
+const stateSuffix = ".s3cli-state"
+
+// downloadState — какие части объекта уже лежат в частично скачанном файле
+type downloadState struct {
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+	Size         int64     `json:"size"`
+	PartSize     int64     `json:"part_size"`
+	Done         []int32   `json:"done"`
+}
+
+func loadDownloadState(path string) *downloadState {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var st downloadState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil
+	}
+	return &st
+}
+
+func saveDownloadState(path string, st *downloadState) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации состояния: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("не удалось записать %q: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("не удалось заменить %q: %w", path, err)
+	}
+	return nil
+}
+
+// downloadResumable — скачивание диапазонами с докачкой: если объект не менялся
+// (ETag, LastModified, размер), тянем только недостающие части, иначе начинаем заново.
+// Данные копятся в скрытом .<имя>.s3cli-partial и встают на место только целиком.
+func downloadResumable(ctx context.Context, s3c *s3.Client, bucket, key string, versionID *string, localPath string, head *s3.HeadObjectOutput, lim *Limiter, bar *progressbar.ProgressBar) error {
+	size := aws.ToInt64(head.ContentLength)
+	etag := aws.ToString(head.ETag)
+	lastMod := aws.ToTime(head.LastModified)
+	partSize := choosePartSize(size)
+	partsTotal := int32((size + partSize - 1) / partSize)
+	partialPath, statePath := partialPaths(localPath)
+
+	st := loadDownloadState(statePath)
+	flags := os.O_RDWR | os.O_CREATE
+	if st == nil || st.ETag != etag || !st.LastModified.Equal(lastMod) || st.Size != size || st.PartSize != partSize {
+		// объекта в таком виде мы ещё не видели — старт с нуля
+		st = &downloadState{ETag: etag, LastModified: lastMod, Size: size, PartSize: partSize}
+		flags |= os.O_TRUNC
+	} else if _, err := os.Stat(partialPath); err != nil {
+		st.Done = nil
+	}
+
+	f, err := os.OpenFile(partialPath, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть файл %q: %w", partialPath, err)
+	}
+	if err := saveDownloadState(statePath, st); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	done := make(map[int32]bool, len(st.Done))
+	for _, n := range st.Done {
+		if n >= 1 && n <= partsTotal && !done[n] {
+			done[n] = true
+			if bar != nil {
+				_ = bar.Add64(partLen(n, partSize, size))
+			}
+		}
+	}
+
+	partsCh := make(chan int32, partsTotal)
+	for n := int32(1); n <= partsTotal; n++ {
+		if !done[n] {
+			partsCh <- n
+		}
+	}
+	close(partsCh)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	for w := 0; w < partWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range partsCh {
+				if ctx.Err() != nil {
+					return
+				}
+				off := int64(n-1) * partSize
+				l := partLen(n, partSize, size)
+				out, err := s3c.GetObject(ctx, &s3.GetObjectInput{
+					Bucket:    aws.String(bucket),
+					Key:       aws.String(key),
+					VersionId: versionID,
+					Range:     aws.String(fmt.Sprintf("bytes=%d-%d", off, off+l-1)),
+					IfMatch:   aws.String(etag),
+				})
+				if err != nil {
+					fail(fmt.Errorf("ошибка чтения диапазона %d-%d: %w", off, off+l-1, err))
+					return
+				}
+				pw := &progressWriterAt{ctx: ctx, f: f, bar: bar, l: lim}
+				written, err := io.Copy(io.NewOffsetWriter(pw, off), io.LimitReader(out.Body, l))
+				_ = out.Body.Close()
+				if err != nil {
+					fail(fmt.Errorf("ошибка записи в %q: %w", partialPath, err))
+					return
+				}
+				if written != l {
+					// соединение оборвалось посреди части — часть не готова, повтор докачает её заново
+					fail(fmt.Errorf("диапазон %d-%d получен не целиком (%d из %d байт): %w", off, off+l-1, written, l, io.ErrUnexpectedEOF))
+					return
+				}
+				mu.Lock()
+				st.Done = append(st.Done, n)
+				_ = saveDownloadState(statePath, st)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		// недокачанный файл и состояние оставляем — следующий запуск с --continue докачает остаток
+		_ = f.Close()
+		return firstErr
+	}
+
+	if err := f.Truncate(size); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("не удалось обрезать %q: %w", partialPath, err)
+	}
+	if err := commitTemp(f, localPath); err != nil {
+		return err
+	}
+	_ = os.Remove(statePath)
+	return nil
+}
@@ -0,0 +1,94 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// DefaultRetries — число повторов элемента, если не задано ни флагом, ни в алиасе
+const DefaultRetries = 3
+
+// RetryPolicy — повтор целого элемента передачи (файла, объекта) с экспоненциальной
+// задержкой и джиттером. Работает поверх ретраев SDK для отдельных запросов.
+type RetryPolicy struct {
+	// Max — сколько раз повторять после первой неудачи (0 — не повторять)
+	Max       int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func NewRetryPolicy(max int) RetryPolicy {
+	return RetryPolicy{
+		Max:       max,
+		BaseDelay: 500 * time.Millisecond,
+		MaxDelay:  30 * time.Second,
+	}
+}
+
+// Do — выполнить fn, повторяя при временных ошибках
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.Max || ctx.Err() != nil || !IsRetryable(err) {
+			return err
+		}
+		t := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// backoff — «full jitter»: случайная задержка от 0 до base*2^attempt, не больше MaxDelay
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MaxDelay
+	if attempt < 30 {
+		if exp := p.BaseDelay << attempt; exp > 0 && exp < d {
+			d = exp
+		}
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+// IsRetryable — временная ли ошибка: 5xx, 429, SlowDown и подобные, таймауты, обрывы соединения
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var re *smithyhttp.ResponseError
+	if errors.As(err, &re) {
+		if st := re.HTTPStatusCode(); st >= 500 || st == 429 {
+			return true
+		}
+	}
+	var ae smithy.APIError
+	if errors.As(err, &ae) {
+		switch ae.ErrorCode() {
+		case "SlowDown", "RequestTimeout", "InternalError", "ServiceUnavailable",
+			"Throttling", "ThrottlingException", "RequestLimitExceeded", "RequestTimeTooSkewed":
+			return true
+		}
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,78 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ErrUnsafeKey — ключ нельзя безопасно превратить в путь внутри каталога назначения
+var ErrUnsafeKey = errors.New("небезопасный ключ")
+
+// localPathFor — путь на диске для части ключа rel (относительно префикса) внутри root.
+// Абсолютные пути, сегменты ".." и NUL отклоняются; с sanitize они переписываются:
+// ведущие "/" отбрасываются, ".." становится "__", NUL — "_".
+func localPathFor(root, rel string, sanitize bool) (string, error) {
+	if sanitize {
+		rel = sanitizeRel(rel)
+	} else if why := unsafeRel(rel); why != "" {
+		return "", fmt.Errorf("%w: %s", ErrUnsafeKey, why)
+	}
+
+	lp := filepath.Join(root, filepath.FromSlash(rel))
+	// последняя проверка уже по готовому пути
+	r, err := filepath.Rel(root, lp)
+	switch {
+	case err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)):
+		return "", fmt.Errorf("%w: путь выходит за пределы %q", ErrUnsafeKey, root)
+	case r == ".":
+		return "", fmt.Errorf("%w: пустое имя файла", ErrUnsafeKey)
+	}
+	return lp, nil
+}
+
+// unsafeRel — чем опасен rel, или "" если ничем
+func unsafeRel(rel string) string {
+	if strings.ContainsRune(rel, 0) {
+		return "содержит NUL"
+	}
+	if strings.HasPrefix(rel, "/") || filepath.IsAbs(filepath.FromSlash(rel)) || filepath.VolumeName(filepath.FromSlash(rel)) != "" {
+		return "абсолютный путь"
+	}
+	for _, seg := range splitSegments(rel) {
+		if seg == ".." {
+			return `содержит ".."`
+		}
+	}
+	return ""
+}
+
+func sanitizeRel(rel string) string {
+	rel = strings.ReplaceAll(rel, "\x00", "_")
+	segs := splitSegments(rel)
+	out := make([]string, 0, len(segs))
+	for i, seg := range segs {
+		switch {
+		case seg == "" || seg == ".":
+			// пустые сегменты (ведущий "/", "a//b") и "." просто выбрасываем
+			continue
+		case seg == "..":
+			seg = "__"
+		case i == 0 && strings.HasSuffix(seg, ":"):
+			// C: и подобные — только на Windows, но переписываем везде одинаково
+			seg = strings.TrimSuffix(seg, ":") + "_"
+		}
+		out = append(out, seg)
+	}
+	return strings.Join(out, "/")
+}
+
+// splitSegments — сегменты ключа; на Windows "\" тоже разделитель
+func splitSegments(rel string) []string {
+	if runtime.GOOS == "windows" {
+		rel = strings.ReplaceAll(rel, `\`, "/")
+	}
+	return strings.Split(rel, "/")
+}
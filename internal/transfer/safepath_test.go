@@ -0,0 +1,51 @@
+package transfer
+
+import "testing"
+
+func TestUnsafeRel(t *testing.T) {
+	tests := []struct {
+		rel    string
+		unsafe bool
+	}{
+		{"a.txt", false},
+		{"a/b/c.txt", false},
+		{"a..b/c", false},
+		{"..a/b", false},
+		{"./a", false},
+		{"a//b", false},
+		{"/etc/passwd", true},
+		{"../evil.txt", true},
+		{"a/../../evil.txt", true},
+		{"a/..", true},
+		{"a\x00b", true},
+	}
+	for _, tt := range tests {
+		if got := unsafeRel(tt.rel); (got != "") != tt.unsafe {
+			t.Errorf("unsafeRel(%q) = %q, небезопасный: %v", tt.rel, got, tt.unsafe)
+		}
+	}
+}
+
+func TestSanitizeRel(t *testing.T) {
+	tests := []struct {
+		rel, want string
+	}{
+		{"a/b.txt", "a/b.txt"},
+		{"/abs.txt", "abs.txt"},
+		{"../../evil.txt", "__/__/evil.txt"},
+		{"a/../b", "a/__/b"},
+		{"a//./b", "a/b"},
+		{"C:/x", "C_/x"},
+		{"a/C:/x", "a/C:/x"},
+		{"a\x00b", "a_b"},
+	}
+	for _, tt := range tests {
+		got := sanitizeRel(tt.rel)
+		if got != tt.want {
+			t.Errorf("sanitizeRel(%q) = %q, ожидалось %q", tt.rel, got, tt.want)
+		}
+		if why := unsafeRel(got); why != "" {
+			t.Errorf("sanitizeRel(%q) = %q всё ещё небезопасен: %s", tt.rel, got, why)
+		}
+	}
+}
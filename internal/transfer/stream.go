@@ -0,0 +1,94 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/schollz/progressbar/v3"
+)
+
+// StreamCopy — копирование между разными endpoint'ами: тело GetObject источника
+// сразу уходит в multipart-загрузку приёмника, локальный диск не используется
+func StreamCopy(ctx context.Context, src, dst *s3.Client, srcBucket, srcKey, dstBucket, dstKey string, opts CopyOptions) error {
+	up := manager.NewUploader(dst)
+	err := opts.Retry.Do(ctx, func() error {
+		return streamOne(ctx, src, dst, up, srcBucket, srcKey, dstBucket, dstKey, opts.Move, opts.Limiter, opts.ShowProgress)
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка копирования s3://%s/%s -> s3://%s/%s: %w", srcBucket, srcKey, dstBucket, dstKey, err)
+	}
+	return nil
+}
+
+func streamOne(ctx context.Context, src, dst *s3.Client, up *manager.Uploader, srcBucket, srcKey, dstBucket, dstKey string, move bool, lim *Limiter, showProgress bool) error {
+	out, err := src.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return err
+	}
+	defer out.Body.Close()
+	size := aws.ToInt64(out.ContentLength)
+
+	body := limitReader(ctx, out.Body, lim)
+	if showProgress {
+		bar := progressbar.NewOptions64(
+			size,
+			progressbar.OptionSetDescription(fmt.Sprintf("CP %s", path.Base(srcKey))),
+			progressbar.OptionSetWriter(os.Stderr),
+			progressbar.OptionShowBytes(true),
+			progressbar.OptionThrottle(100e6),
+			progressbar.OptionClearOnFinish(),
+			progressbar.OptionSetWidth(20),
+		)
+		body = io.TeeReader(body, bar)
+	}
+
+	_, err = up.Upload(ctx, &s3.PutObjectInput{
+		Bucket:             aws.String(dstBucket),
+		Key:                aws.String(dstKey),
+		Body:               body,
+		ContentType:        out.ContentType,
+		ContentEncoding:    out.ContentEncoding,
+		ContentDisposition: out.ContentDisposition,
+		CacheControl:       out.CacheControl,
+		Metadata:           out.Metadata,
+	}, func(u *manager.Uploader) {
+		// тело — обычный поток, сам uploader размер не узнает: без этого части по 5 MiB
+		// и объекты больше ~48.8 GiB упираются в лимит 10000 частей
+		u.PartSize = max(manager.DefaultUploadPartSize, (size+maxParts-1)/maxParts)
+	})
+	if err != nil {
+		return err
+	}
+	if !move {
+		return nil
+	}
+
+	// источник удаляем, только если копия на месте и того же размера
+	check, err := dst.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(dstBucket),
+		Key:    aws.String(dstKey),
+	})
+	if err != nil {
+		return fmt.Errorf("копия не найдена, источник не удалён: %w", err)
+	}
+	if aws.ToInt64(check.ContentLength) != size {
+		return fmt.Errorf("размер копии не совпадает (%d != %d), источник не удалён", aws.ToInt64(check.ContentLength), size)
+	}
+	_, err = src.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return fmt.Errorf("копия создана, но источник не удалён: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package transfer
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wolfsTail/s3cli/internal/filter"
+)
+
+// SyncEntry — файл или объект, участвующий в синхронизации.
+// Rel — путь относительно корня (через "/"), Local — путь на диске для локальной стороны.
+type SyncEntry struct {
+	Rel     string
+	Local   string
+	Size    int64
+	ModTime time.Time
+	ETag    string
+}
+
+type CompareMode int
+
+const (
+	// CompareMtime — размер + время изменения (источник новее приёмника)
+	CompareMtime CompareMode = iota
+	// CompareETag — размер + ETag (MD5 локального файла)
+	CompareETag
+)
+
+// SyncPlan — что нужно передать и что удалить на приёмнике
+type SyncPlan struct {
+	Copy      []SyncEntry
+	Delete    []SyncEntry
+	Unchanged int
+}
+
+// WalkLocal — все файлы под каталогом dir, прошедшие фильтр (nil — все)
+func WalkLocal(dir string, flt *filter.Filter) ([]SyncEntry, error) {
+	var out []SyncEntry
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && flt.SkipDir(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !flt.Match(rel) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out = append(out, SyncEntry{
+			Rel:     rel,
+			Local:   p,
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка обхода каталога %q: %w", dir, err)
+	}
+	return out, nil
+}
+
+// PlanSync — сравнить источник и приёмник; с withDelete лишнее на приёмнике попадает в Delete
+func PlanSync(src, dst []SyncEntry, mode CompareMode, withDelete bool) (SyncPlan, error) {
+	dstByRel := make(map[string]SyncEntry, len(dst))
+	for _, e := range dst {
+		dstByRel[e.Rel] = e
+	}
+
+	var plan SyncPlan
+	seen := make(map[string]bool, len(src))
+	for _, s := range src {
+		seen[s.Rel] = true
+		d, ok := dstByRel[s.Rel]
+		if !ok {
+			plan.Copy = append(plan.Copy, s)
+			continue
+		}
+		changed, err := entryChanged(s, d, mode)
+		if err != nil {
+			return SyncPlan{}, err
+		}
+		if changed {
+			plan.Copy = append(plan.Copy, s)
+		} else {
+			plan.Unchanged++
+		}
+	}
+	if withDelete {
+		for _, d := range dst {
+			if !seen[d.Rel] {
+				plan.Delete = append(plan.Delete, d)
+			}
+		}
+	}
+	sort.Slice(plan.Copy, func(i, j int) bool { return plan.Copy[i].Rel < plan.Copy[j].Rel })
+	sort.Slice(plan.Delete, func(i, j int) bool { return plan.Delete[i].Rel < plan.Delete[j].Rel })
+	return plan, nil
+}
+
+func entryChanged(src, dst SyncEntry, mode CompareMode) (bool, error) {
+	if src.Size != dst.Size {
+		return true, nil
+	}
+	if mode == CompareETag {
+		// сначала удалённая сторона: если её ETag не годится, локальный файл не читаем
+		a, b := src, dst
+		if a.Local != "" {
+			a, b = b, a
+		}
+		ea, err := entryETag(a)
+		if err != nil {
+			return false, err
+		}
+		// ETag multipart-объекта — не MD5 содержимого, тогда сравниваем по времени
+		if ea != "" {
+			eb, err := entryETag(b)
+			if err != nil {
+				return false, err
+			}
+			if eb != "" {
+				return ea != eb, nil
+			}
+		}
+	}
+	return src.ModTime.After(dst.ModTime), nil
+}
+
+func entryETag(e SyncEntry) (string, error) {
+	if e.Local == "" {
+		etag := strings.Trim(e.ETag, `"`)
+		if strings.Contains(etag, "-") {
+			return "", nil
+		}
+		return etag, nil
+	}
+	f, err := os.Open(e.Local)
+	if err != nil {
+		return "", fmt.Errorf("не удалось открыть %q: %w", e.Local, err)
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("ошибка чтения %q: %w", e.Local, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
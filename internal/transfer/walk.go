@@ -0,0 +1,121 @@
+package transfer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/wolfsTail/s3cli/internal/filter"
+)
+
+// LinkMode — что делать с символическими ссылками
+type LinkMode int
+
+const (
+	// LinksDefault — ссылки на файлы грузятся как файлы, ссылки на каталоги пропускаются
+	LinksDefault LinkMode = iota
+	// LinksFollow — заходить и в ссылки на каталоги (с защитой от циклов)
+	LinksFollow
+	// LinksStore — сохранять саму ссылку: пустой объект с целью в метаданных
+	LinksStore
+)
+
+// walkTree — файлы под root для загрузки; rel — путь относительно root через "/".
+// Возвращает и предупреждения о том, что пропущено.
+func walkTree(root string, mode LinkMode, flt *filter.Filter) ([]UploadItem, []string, error) {
+	var (
+		items []UploadItem
+		warns []string
+	)
+	var walk func(dir, rel string, ancestors []string) error
+	walk = func(dir, rel string, ancestors []string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			p := filepath.Join(dir, e.Name())
+			r := path.Join(rel, e.Name())
+
+			if e.Type()&fs.ModeSymlink != 0 {
+				if mode == LinksStore {
+					target, err := os.Readlink(p)
+					if err != nil {
+						return err
+					}
+					if flt.Match(r) {
+						items = append(items, UploadItem{Local: p, Key: r, Link: target})
+					}
+					continue
+				}
+				fi, err := os.Stat(p)
+				if err != nil || !fi.IsDir() {
+					// битая ссылка тоже попадёт в список и честно не загрузится
+					if flt.Match(r) {
+						items = append(items, UploadItem{Local: p, Key: r})
+					}
+					continue
+				}
+				if mode != LinksFollow {
+					warns = append(warns, fmt.Sprintf("%s: ссылка на каталог, пропущено (см. --follow-symlinks)", p))
+					continue
+				}
+				real, err := filepath.EvalSymlinks(p)
+				if err != nil {
+					return err
+				}
+				if slices.ContainsFunc(ancestors, func(a string) bool { return within(a, real) }) {
+					warns = append(warns, fmt.Sprintf("%s: ссылка ведёт в свой же родительский каталог, пропущено", p))
+					continue
+				}
+				if flt.SkipDir(r) {
+					continue
+				}
+				if err := walk(p, r, append(ancestors[:len(ancestors):len(ancestors)], real)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if e.IsDir() {
+				if flt.SkipDir(r) {
+					continue
+				}
+				real, err := filepath.EvalSymlinks(p)
+				if err != nil {
+					return err
+				}
+				if err := walk(p, r, append(ancestors[:len(ancestors):len(ancestors)], real)); err != nil {
+					return err
+				}
+				continue
+			}
+			if !e.Type().IsRegular() {
+				warns = append(warns, fmt.Sprintf("%s: не обычный файл, пропущено", p))
+				continue
+			}
+			if flt.Match(r) {
+				items = append(items, UploadItem{Local: p, Key: r})
+			}
+		}
+		return nil
+	}
+
+	real, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := walk(root, "", []string{real}); err != nil {
+		return nil, nil, err
+	}
+	return items, warns, nil
+}
+
+// within — p совпадает с dir или лежит внутри него
+func within(p, dir string) bool {
+	return p == dir || strings.HasPrefix(p, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}